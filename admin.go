@@ -0,0 +1,186 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requireAdmin exige o cabeçalho "Authorization: Bearer <ADMIN_TOKEN>" antes de chamar o manipulador.
+// A comparação é feita em tempo constante para não vazar o token por temporização.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "Não autorizado.", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// adminListPuzzlesHandler lista os registros do cache que atendem aos filtros da query string.
+func (s *Server) adminListPuzzlesHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseCacheFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := s.store.ListCachedPuzzles(filter)
+	if err != nil {
+		log.Printf("Erro ao listar o cache: %v", err)
+		http.Error(w, "Erro interno do servidor: Falha ao listar o cache.", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, adminPuzzleList{Items: entries, Limit: filter.limit(), Offset: filter.Offset})
+}
+
+// adminGetPuzzleHandler retorna um registro do cache com os parâmetros da requisição e a resposta.
+func (s *Server) adminGetPuzzleHandler(w http.ResponseWriter, r *http.Request) {
+	hash := r.PathValue("hash")
+	entry, err := s.store.GetCachedPuzzleEntry(hash)
+	if err != nil {
+		log.Printf("Erro ao obter o registro %s do cache: %v", hash, err)
+		http.Error(w, "Erro interno do servidor: Falha ao obter o registro.", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(w, "Registro não encontrado no cache.", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// adminDeletePuzzleHandler remove um registro do cache pelo hash.
+func (s *Server) adminDeletePuzzleHandler(w http.ResponseWriter, r *http.Request) {
+	hash := r.PathValue("hash")
+	deleted, err := s.store.DeleteCachedPuzzle(hash)
+	if err != nil {
+		log.Printf("Erro ao remover o registro %s do cache: %v", hash, err)
+		http.Error(w, "Erro interno do servidor: Falha ao remover o registro.", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Registro não encontrado no cache.", http.StatusNotFound)
+		return
+	}
+	log.Printf("Admin removeu o registro do cache: %s", hash)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adminPurgePuzzlesHandler remove em lote os registros que atendem aos filtros.
+// Para evitar apagar o cache inteiro por engano, um filtro vazio só é aceito com all=true.
+func (s *Server) adminPurgePuzzlesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, err := parseCacheFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.IsEmpty() && query.Get("all") != "true" {
+		http.Error(w, "Informe ao menos um filtro ou all=true para expurgar todo o cache.", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := s.store.PurgeCachedPuzzles(filter)
+	if err != nil {
+		log.Printf("Erro ao expurgar o cache com o filtro %+v: %v", filter, err)
+		http.Error(w, "Erro interno do servidor: Falha ao expurgar o cache.", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin expurgou %d registros do cache com o filtro %+v", deleted, filter)
+	writeJSON(w, http.StatusOK, adminPurgeResult{Deleted: deleted})
+}
+
+// parseCacheFilter converte os parâmetros da query string em um CacheFilter.
+// "from" e "to" aceitam RFC 3339 ou uma data AAAA-MM-DD (interpretada em UTC);
+// com uma data simples, "to" inclui o dia inteiro.
+func parseCacheFilter(query url.Values) (CacheFilter, error) {
+	filter := CacheFilter{
+		GameType:   query.Get("gameType"),
+		Difficulty: query.Get("difficulty"),
+		Language:   query.Get("language"),
+		Topic:      query.Get("topic"),
+	}
+
+	var err error
+	if v := query.Get("from"); v != "" {
+		if filter.CreatedFrom, _, err = parseFilterTime(v); err != nil {
+			return filter, fmt.Errorf("parâmetro from inválido: %w", err)
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		var dateOnly bool
+		if filter.CreatedTo, dateOnly, err = parseFilterTime(v); err != nil {
+			return filter, fmt.Errorf("parâmetro to inválido: %w", err)
+		}
+		if dateOnly {
+			filter.CreatedTo = filter.CreatedTo.AddDate(0, 0, 1)
+		}
+	}
+	filter.Limit, filter.Offset, err = parsePagination(query)
+	return filter, err
+}
+
+// parsePagination lê os parâmetros limit e offset das listagens administrativas.
+func parsePagination(query url.Values) (limit, offset int, err error) {
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return 0, 0, fmt.Errorf("parâmetro limit inválido: %q", v)
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("parâmetro offset inválido: %q", v)
+		}
+	}
+	return limit, offset, nil
+}
+
+// adminListWebhookDeliveriesHandler lista as tentativas de entrega de webhooks, opcionalmente de um job.
+func (s *Server) adminListWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := WebhookDeliveryFilter{JobID: query.Get("jobId")}
+	var err error
+	if filter.Limit, filter.Offset, err = parsePagination(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := s.store.ListWebhookDeliveries(filter)
+	if err != nil {
+		log.Printf("Erro ao listar as entregas de webhooks: %v", err)
+		http.Error(w, "Erro interno do servidor: Falha ao listar as entregas de webhooks.", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, adminWebhookDeliveryList{Items: deliveries, Limit: filter.limit(), Offset: filter.Offset})
+}
+
+// parseFilterTime interpreta um instante em RFC 3339 ou uma data AAAA-MM-DD.
+// O segundo retorno indica se o valor era apenas uma data.
+func parseFilterTime(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
+
+// writeJSON serializa v como resposta JSON com o status informado.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Erro ao escrever a resposta JSON: %v", err)
+	}
+}
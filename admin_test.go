@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// adminRequest envia a requisição ao mux com o cabeçalho Authorization informado (vazio para nenhum).
+func adminRequest(handler http.Handler, method, target, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	handler := (&Server{store: NewMemoryStore(), adminToken: "segredo"}).routes()
+
+	for _, authorization := range []string{"", "segredo", "Bearer errado", "Bearer segredo2", "Basic c2VncmVkbw=="} {
+		rec := adminRequest(handler, http.MethodGet, "/admin/puzzles", authorization)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status %d, quer 401", authorization, rec.Code)
+		}
+		if got := rec.Header().Get("WWW-Authenticate"); got != `Bearer realm="admin"` {
+			t.Errorf("Authorization %q: WWW-Authenticate %q, quer Bearer", authorization, got)
+		}
+	}
+	if rec := adminRequest(handler, http.MethodGet, "/admin/puzzles", "Bearer segredo"); rec.Code != http.StatusOK {
+		t.Errorf("token correto: status %d, quer 200", rec.Code)
+	}
+
+	// Sem ADMIN_TOKEN, os endpoints /admin não existem.
+	handler = (&Server{store: NewMemoryStore()}).routes()
+	if rec := adminRequest(handler, http.MethodGet, "/admin/puzzles", "Bearer "); rec.Code != http.StatusNotFound {
+		t.Errorf("sem ADMIN_TOKEN: status %d, quer 404", rec.Code)
+	}
+}
+
+func TestParseCacheFilter(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		query   string
+		want    CacheFilter
+		wantErr bool
+	}{
+		{query: "", want: CacheFilter{}},
+		{query: "gameType=quiz&topic=animais&limit=10&offset=20", want: CacheFilter{GameType: "quiz", Topic: "animais", Limit: 10, Offset: 20}},
+		{query: "from=2026-10-18&to=2026-10-18", want: CacheFilter{CreatedFrom: day, CreatedTo: day.AddDate(0, 0, 1)}}, // to inclui o dia inteiro
+		{query: "to=2026-10-18T12:00:00Z", want: CacheFilter{CreatedTo: day.Add(12 * time.Hour)}},
+		{query: "limit=x", wantErr: true},
+		{query: "limit=-1", wantErr: true},
+		{query: "offset=1.5", wantErr: true},
+		{query: "offset=-1", wantErr: true},
+		{query: "from=18/10/2026", wantErr: true},
+		{query: "to=2026-13-01", wantErr: true},
+		{query: "from=ontem", wantErr: true},
+	}
+	for _, tt := range tests {
+		query, _ := url.ParseQuery(tt.query)
+		got, err := parseCacheFilter(query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseCacheFilter(%q) = %+v, quer erro", tt.query, got)
+			}
+			continue
+		}
+		if err != nil || !got.CreatedFrom.Equal(tt.want.CreatedFrom) || !got.CreatedTo.Equal(tt.want.CreatedTo) {
+			t.Errorf("parseCacheFilter(%q) = %+v, %v; quer %+v", tt.query, got, err, tt.want)
+			continue
+		}
+		got.CreatedFrom, got.CreatedTo = tt.want.CreatedFrom, tt.want.CreatedTo
+		if got != tt.want {
+			t.Errorf("parseCacheFilter(%q) = %+v, quer %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestAdminPuzzleEndpoints(t *testing.T) {
+	store := NewMemoryStore()
+	run := testRun(t)
+	now := time.Now().UTC()
+	puzzles := []*CachedPuzzle{
+		testPuzzle(run, 1, PuzzleRequest{GameType: "quiz", Difficulty: "easy", Language: "pt"}, now),
+		testPuzzle(run, 2, PuzzleRequest{GameType: "crossword", Difficulty: "hard", Language: "en"}, now),
+	}
+	for _, p := range puzzles {
+		if err := store.SaveCachedPuzzle(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := (&Server{store: store, adminToken: "segredo"}).routes()
+	const token = "Bearer segredo"
+
+	if rec := adminRequest(handler, http.MethodGet, "/admin/puzzles/"+puzzles[0].RequestHash, token); rec.Code != http.StatusOK {
+		t.Errorf("GET de um hash existente: status %d, quer 200", rec.Code)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if rec := adminRequest(handler, method, "/admin/puzzles/desconhecido", token); rec.Code != http.StatusNotFound {
+			t.Errorf("%s de um hash desconhecido: status %d, quer 404", method, rec.Code)
+		}
+	}
+	if rec := adminRequest(handler, http.MethodGet, "/admin/puzzles?from=ontem", token); rec.Code != http.StatusBadRequest {
+		t.Errorf("listagem com from inválido: status %d, quer 400", rec.Code)
+	}
+
+	// Um expurgo sem filtros é recusado e não remove nada.
+	for _, target := range []string{"/admin/puzzles", "/admin/puzzles?all=1", "/admin/puzzles?limit=10"} {
+		if rec := adminRequest(handler, http.MethodDelete, target, token); rec.Code != http.StatusBadRequest {
+			t.Errorf("DELETE %s: status %d, quer 400", target, rec.Code)
+		}
+	}
+	if entries, _ := store.ListCachedPuzzles(CacheFilter{}); len(entries) != len(puzzles) {
+		t.Fatalf("expurgo recusado removeu registros: restam %d, quer %d", len(entries), len(puzzles))
+	}
+
+	rec := adminRequest(handler, http.MethodDelete, "/admin/puzzles?gameType=quiz", token)
+	var result adminPurgeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); rec.Code != http.StatusOK || err != nil || result.Deleted != 1 {
+		t.Errorf("DELETE /admin/puzzles?gameType=quiz: status %d, %s; quer 200 com deleted 1", rec.Code, rec.Body)
+	}
+	if rec := adminRequest(handler, http.MethodDelete, "/admin/puzzles/"+puzzles[1].RequestHash, token); rec.Code != http.StatusNoContent {
+		t.Errorf("DELETE de um hash existente: status %d, quer 204", rec.Code)
+	}
+	if rec := adminRequest(handler, http.MethodDelete, "/admin/puzzles?all=true", token); rec.Code != http.StatusOK {
+		t.Errorf("DELETE /admin/puzzles?all=true: status %d, quer 200", rec.Code)
+	}
+}
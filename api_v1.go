@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// PuzzleEnvelope é a resposta dos endpoints /v1: o quebra-cabeça gerado, sem campos adicionados pelo
+// proxy, acompanhado dos metadados da geração. Os endpoints sem prefixo mantêm o formato legado, em que
+// puzzleId e puzzleDate são misturados aos dados do quebra-cabeça.
+type PuzzleEnvelope struct {
+	Puzzle        json.RawMessage `json:"puzzle" openapi:"puzzle"`
+	PuzzleID      string          `json:"puzzleId" description:"Stable public ID, usable in GET /v1/puzzles/{id}"`
+	PuzzleDate    string          `json:"puzzleDate,omitempty" description:"Date (YYYY-MM-DD) of a daily puzzle"`
+	Cached        bool            `json:"cached" description:"Whether the puzzle was served from the cache"`
+	GeneratedAt   time.Time       `json:"generatedAt" description:"When the puzzle was generated"`
+	Model         string          `json:"model,omitempty" description:"Gemini model that generated the puzzle (empty for locally generated games)"`
+	PromptVersion string          `json:"promptVersion,omitempty" description:"Version of the prompt template used (empty for locally generated games)"`
+	SchemaVersion string          `json:"schemaVersion,omitempty" description:"Version of the game data format; changes whenever the data model changes"`
+}
+
+// envelope monta a resposta /v1 do quebra-cabeça.
+func (p *generatedPuzzle) envelope() *PuzzleEnvelope {
+	return &PuzzleEnvelope{
+		Puzzle:        p.Entry.ResponseData,
+		PuzzleID:      p.PuzzleID,
+		PuzzleDate:    p.Entry.PuzzleDate,
+		Cached:        p.Cached,
+		GeneratedAt:   p.Entry.CreatedAt,
+		Model:         p.Entry.Model,
+		PromptVersion: p.Entry.PromptVersion,
+		SchemaVersion: p.Entry.SchemaVersion,
+	}
+}
+
+// jobV1 é a resposta dos endpoints /v1/jobs: o job com o quebra-cabeça no formato de PuzzleEnvelope.
+// O campo Puzzle sobrepõe o de Job na serialização JSON.
+type jobV1 struct {
+	Job
+	Puzzle *PuzzleEnvelope `json:"puzzle,omitempty"` // Preenchido quando o job termina com sucesso
+}
+
+// newJobV1 converte o job para a resposta /v1.
+func newJobV1(job Job) jobV1 {
+	return jobV1{Job: job, Puzzle: job.envelope}
+}
+
+// generatePuzzleV1Handler é o manipulador de POST /v1/puzzles, equivalente a POST /generate-puzzle.
+func (s *Server) generatePuzzleV1Handler(w http.ResponseWriter, r *http.Request) {
+	var req PuzzleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Payload de requisição inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	puzzle, err := s.generate(r.Context(), req)
+	if err != nil {
+		log.Printf("Erro ao gerar quebra-cabeça para a requisição %+v: %v", req, err)
+		status, msg := generationErrorStatus(err)
+		http.Error(w, msg, status)
+		return
+	}
+	writeCacheableJSON(w, r, puzzle.envelope())
+}
+
+// getPuzzleV1Handler é o manipulador de GET /v1/puzzles/{id}, equivalente a GET /puzzles/{id}.
+func (s *Server) getPuzzleV1Handler(w http.ResponseWriter, r *http.Request) {
+	puzzleID := r.PathValue("id")
+	entry, err := s.store.GetCachedPuzzleByID(puzzleID)
+	if err != nil {
+		log.Printf("Erro ao obter o quebra-cabeça %s: %v", puzzleID, err)
+		http.Error(w, "Erro interno do servidor: Falha ao obter o quebra-cabeça.", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(w, "Quebra-cabeça não encontrado.", http.StatusNotFound)
+		return
+	}
+	puzzle := newGeneratedPuzzle(entry, true)
+	writeCacheableJSON(w, r, puzzle.envelope())
+}
+
+// dailyV1Handler é o manipulador de GET /v1/daily, equivalente a GET /daily.
+func (s *Server) dailyV1Handler(w http.ResponseWriter, r *http.Request) {
+	puzzle, ok := s.serveDaily(w, r)
+	if !ok {
+		return
+	}
+	writeCacheableJSON(w, r, puzzle.envelope())
+}
+
+// createJobV1Handler é o manipulador de POST /v1/jobs, equivalente a POST /jobs.
+func (s *Server) createJobV1Handler(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.createJob(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, newJobV1(job))
+}
+
+// getJobV1Handler é o manipulador de GET /v1/jobs/{id}, equivalente a GET /jobs/{id}.
+func (s *Server) getJobV1Handler(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.Get(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Job não encontrado.", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, newJobV1(job))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// v1Request envia a requisição ao mux, com o corpo e o If-None-Match informados (vazios para nenhum).
+func v1Request(handler http.Handler, method, target, body, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeEnvelope decodifica a resposta /v1 e retorna também os nomes dos campos presentes, em ordem alfabética.
+func decodeEnvelope(t *testing.T, body []byte) (PuzzleEnvelope, []string) {
+	t.Helper()
+	var envelope PuzzleEnvelope
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("resposta %s: %v", body, err)
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return envelope, names
+}
+
+func TestPuzzleV1Envelope(t *testing.T) {
+	s := &Server{
+		store: NewMemoryStore(),
+		daily: DailyConfig{Difficulty: "easy", Topics: []string{"numbers"}, Location: time.UTC},
+	}
+	handler := s.routes()
+	const body = `{"gameType":"sudoku","difficulty":"easy"}`
+
+	first := v1Request(handler, http.MethodPost, "/v1/puzzles", body, "")
+	if first.Code != http.StatusOK || first.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("POST /v1/puzzles: status %d (%s): %s", first.Code, first.Header().Get("Content-Type"), first.Body)
+	}
+	envelope, fields := decodeEnvelope(t, first.Body.Bytes())
+	// O sudoku é gerado localmente: sem model nem promptVersion, e sem puzzleDate por não ser do dia.
+	if want := []string{"cached", "generatedAt", "puzzle", "puzzleId", "schemaVersion"}; !slices.Equal(fields, want) {
+		t.Errorf("campos do envelope = %v, quer %v", fields, want)
+	}
+	if envelope.Cached || envelope.PuzzleID == "" || envelope.GeneratedAt.IsZero() || envelope.SchemaVersion == "" {
+		t.Errorf("envelope = %+v, quer cached false com puzzleId, generatedAt e schemaVersion", envelope)
+	}
+	entry, err := s.store.GetCachedPuzzleByID(envelope.PuzzleID)
+	if err != nil || entry == nil {
+		t.Fatalf("GetCachedPuzzleByID(%q) = %v, %v", envelope.PuzzleID, entry, err)
+	}
+	// puzzle é o quebra-cabeça gerado como está no cache, sem os campos que os endpoints legados adicionam.
+	if !sameJSON(t, envelope.Puzzle, entry.ResponseData) || strings.Contains(string(envelope.Puzzle), "puzzleId") {
+		t.Errorf("puzzle = %s, quer %s", envelope.Puzzle, entry.ResponseData)
+	}
+
+	// O ETag é o hash do envelope: a mesma versão servida do cache muda cached e, com ele, o ETag.
+	firstETag := first.Header().Get("ETag")
+	if firstETag != etagFor(first.Body.Bytes()) {
+		t.Errorf("ETag %q, quer o hash do corpo %q", firstETag, etagFor(first.Body.Bytes()))
+	}
+	second := v1Request(handler, http.MethodPost, "/v1/puzzles", body, "")
+	if envelope, _ := decodeEnvelope(t, second.Body.Bytes()); !envelope.Cached {
+		t.Errorf("segundo POST /v1/puzzles: cached false, quer true")
+	}
+	if second.Header().Get("ETag") == firstETag {
+		t.Errorf("respostas com cached false e true com o mesmo ETag %q", firstETag)
+	}
+
+	get := v1Request(handler, http.MethodGet, "/v1/puzzles/"+envelope.PuzzleID, "", "")
+	if get.Code != http.StatusOK || get.Body.String() != second.Body.String() {
+		t.Errorf("GET /v1/puzzles/%s: status %d com %s, quer 200 com %s", envelope.PuzzleID, get.Code, get.Body, second.Body)
+	}
+	etag := get.Header().Get("ETag")
+	if rec := v1Request(handler, http.MethodGet, "/v1/puzzles/"+envelope.PuzzleID, "", firstETag); rec.Code != http.StatusOK {
+		t.Errorf("GET com o ETag da resposta com cached false: status %d, quer 200", rec.Code)
+	}
+	if rec := v1Request(handler, http.MethodGet, "/v1/puzzles/"+envelope.PuzzleID, "", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("GET com o ETag atual: status %d com %d bytes, quer 304 sem corpo", rec.Code, rec.Body.Len())
+	}
+
+	daily, err := s.dailyPuzzle(context.Background(), "sudoku", "en", "2026-10-18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := v1Request(handler, http.MethodGet, "/v1/puzzles/"+daily.PuzzleID, "", "")
+	if envelope, fields := decodeEnvelope(t, rec.Body.Bytes()); envelope.PuzzleDate != "2026-10-18" || !slices.Contains(fields, "puzzleDate") {
+		t.Errorf("GET /v1/puzzles/%s do dia: puzzleDate %q, quer 2026-10-18", daily.PuzzleID, envelope.PuzzleDate)
+	}
+}
+
+func TestJobV1Envelope(t *testing.T) {
+	s := &Server{store: NewMemoryStore()}
+	finished := make(chan Job, 1)
+	s.jobs = NewJobManager(JobsConfig{MaxConcurrent: 1}, s.generate, func(job Job) { finished <- job })
+	handler := s.routes()
+
+	rec := v1Request(handler, http.MethodPost, "/v1/jobs", `{"gameType":"sudoku","difficulty":"easy"}`, "")
+	var created jobV1
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); rec.Code != http.StatusAccepted || err != nil {
+		t.Fatalf("POST /v1/jobs: status %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Location"); got != "/v1/jobs/"+created.ID {
+		t.Errorf("Location %q, quer /v1/jobs/%s", got, created.ID)
+	}
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("o job não terminou")
+	}
+
+	rec = v1Request(handler, http.MethodGet, "/v1/jobs/"+created.ID, "", "")
+	var job struct {
+		Status JobStatus                  `json:"status"`
+		Puzzle map[string]json.RawMessage `json:"puzzle"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+		t.Fatal(err)
+	}
+	// O puzzle do job é o envelope /v1, não o quebra-cabeça no formato legado.
+	if job.Status != JobSucceeded || job.Puzzle["puzzle"] == nil || job.Puzzle["puzzleId"] == nil || job.Puzzle["gameType"] != nil {
+		t.Errorf("GET /v1/jobs/%s = %s, quer succeeded com o envelope /v1", created.ID, rec.Body)
+	}
+}
+
+// Os erros dos endpoints /v1 são texto simples, como nos endpoints legados.
+func TestV1ErrorFormat(t *testing.T) {
+	s := &Server{store: NewMemoryStore(), jobs: NewJobManager(JobsConfig{MaxConcurrent: 1}, nil, nil)}
+	handler := s.routes()
+
+	tests := []struct {
+		method, target, body string
+		status               int
+		message              string
+	}{
+		{http.MethodPost, "/v1/puzzles", `{`, http.StatusBadRequest, "Payload de requisição inválido"},
+		{http.MethodPost, "/v1/puzzles", `{"gameType":"chess"}`, http.StatusBadRequest, `"chess"`},
+		{http.MethodGet, "/v1/puzzles/desconhecido", "", http.StatusNotFound, "Quebra-cabeça não encontrado."},
+		{http.MethodPost, "/v1/jobs", `{`, http.StatusBadRequest, "Payload de requisição inválido"},
+		{http.MethodGet, "/v1/jobs/desconhecido", "", http.StatusNotFound, "Job não encontrado."},
+	}
+	for _, tt := range tests {
+		rec := v1Request(handler, tt.method, tt.target, tt.body, "")
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.message) {
+			t.Errorf("%s %s: status %d com %q, quer %d com %q", tt.method, tt.target, rec.Code, rec.Body, tt.status, tt.message)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+			t.Errorf("%s %s: Content-Type %q, quer text/plain", tt.method, tt.target, got)
+		}
+		if rec.Header().Get("ETag") != "" {
+			t.Errorf("%s %s: erro com ETag", tt.method, tt.target)
+		}
+	}
+}
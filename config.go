@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config reúne as configurações do servidor lidas das variáveis de ambiente.
+type Config struct {
+	Port   string       // Porta HTTP em que o servidor escuta.
+	Gemini GeminiConfig // Configuração da API Gemini.
+
+	CacheBackend string // Backend de cache: "postgres", "sqlite" ou "memory".
+	DatabaseURL  string // String de conexão PostgreSQL (backend "postgres").
+	SQLitePath   string // Caminho do arquivo SQLite (backend "sqlite").
+
+	AdminToken string // Token Bearer dos endpoints /admin; vazio desativa a API administrativa.
+
+	PromptsDir string // Diretório com templates .tmpl que substituem os prompts embutidos; vazio usa apenas os embutidos.
+
+	Daily DailyConfig // Configuração do quebra-cabeça do dia.
+	CORS  CORSConfig  // Cabeçalhos CORS para clientes web.
+	Jobs  JobsConfig  // Gerações em segundo plano.
+
+	Webhooks WebhookConfig // Callbacks chamados quando um job termina.
+
+	HTTPCache HTTPCacheConfig // Cache-Control e compressão das respostas HTTP.
+
+	IdempotencyRetention time.Duration // Tempo em que uma Idempotency-Key repete a primeira resposta.
+
+	GRPCPort string // Porta da API gRPC; vazio a desativa.
+}
+
+// GeminiConfig controla as chamadas à API Gemini.
+type GeminiConfig struct {
+	APIKeys      []string      // Chaves da API Gemini (GEMINI_API_KEYS, ou apenas GEMINI_API_KEY).
+	KeySelection string        // Escolha da chave: "round-robin" ou "least-throttled".
+	KeyCooldown  time.Duration // Tempo que uma chave fica fora de uso após um 429 sem Retry-After.
+	MaxAttempts  int           // Tentativas por modelo, contando os reparos de respostas inválidas.
+
+	Models ModelChains        // Modelos por tipo de jogo e dificuldade, com os modelos de fallback.
+	HTTP   UpstreamHTTPConfig // Cliente HTTP das chamadas à API Gemini.
+}
+
+// ModelChains contém as cadeias de modelos, em ordem de preferência, indexadas por "gameType/difficulty",
+// "gameType" ou "" (padrão).
+type ModelChains map[string][]string
+
+// defaultGeminiModel é o modelo usado quando GEMINI_MODELS não está definida.
+const defaultGeminiModel = "gemini-2.0-flash"
+
+// For retorna a cadeia de modelos do tipo de jogo e dificuldade: a configuração mais específica
+// entre GEMINI_MODELS_<GAMETYPE>_<DIFFICULTY>, GEMINI_MODELS_<GAMETYPE> e GEMINI_MODELS.
+func (c ModelChains) For(gameType, difficulty string) []string {
+	gameType, difficulty = strings.ToLower(gameType), strings.ToLower(difficulty)
+	for _, key := range []string{gameType + "/" + difficulty, gameType, ""} {
+		if models, ok := c[key]; ok {
+			return models
+		}
+	}
+	return []string{defaultGeminiModel}
+}
+
+// CORSConfig controla os cabeçalhos CORS dos endpoints públicos. Sem origens permitidas, o CORS fica desativado.
+type CORSConfig struct {
+	AllowedOrigins   []string      // Origens permitidas (ex: "https://app.example.com") ou "*" para qualquer uma.
+	AllowedMethods   []string      // Métodos aceitos no preflight.
+	AllowedHeaders   []string      // Cabeçalhos aceitos no preflight.
+	ExposedHeaders   []string      // Cabeçalhos da resposta que o JavaScript da página pode ler (ex: ETag).
+	MaxAge           time.Duration // Tempo que o navegador pode guardar a resposta do preflight.
+	AllowCredentials bool          // Permite cookies e cabeçalhos de autenticação nas requisições.
+}
+
+// JobsConfig controla os jobs de geração em segundo plano (POST /jobs e a API gRPC).
+type JobsConfig struct {
+	MaxConcurrent int           // Gerações simultâneas; os demais jobs esperam na fila.
+	MaxPending    int           // Jobs na fila ou em execução; acima disso, novos jobs são recusados com 429.
+	Retention     time.Duration // Tempo que um job terminado continua consultável.
+}
+
+// WebhookConfig controla a entrega dos callbacks dos jobs (callbackUrl). Sem Secret, callbackUrl é recusado.
+type WebhookConfig struct {
+	Secret         string        // Segredo do HMAC-SHA256 enviado em X-Puzzle-Signature.
+	MaxAttempts    int           // Tentativas de entrega, contando a primeira.
+	InitialBackoff time.Duration // Espera antes da segunda tentativa; dobra a cada nova falha.
+	Timeout        time.Duration // Tempo máximo de cada tentativa.
+	AllowPrivate   bool          // Aceita callbacks em localhost e em redes internas (apenas para desenvolvimento).
+}
+
+// HTTPCacheConfig controla os cabeçalhos de cache HTTP e a compressão das respostas.
+type HTTPCacheConfig struct {
+	CacheControl       map[string]string // Cache-Control por operationId em minúsculas; substitui o padrão da rota.
+	Compression        bool              // Comprime as respostas com brotli ou gzip conforme o Accept-Encoding.
+	CompressionMinSize int               // Tamanho mínimo, em bytes, de uma resposta comprimida.
+}
+
+// DailyConfig controla o endpoint /daily e o agendador que gera os quebra-cabeças do dia antecipadamente.
+type DailyConfig struct {
+	GameTypes       []string       // Tipos de jogo com quebra-cabeça do dia (o primeiro é o padrão).
+	Languages       []string       // Idiomas com quebra-cabeça do dia (o primeiro é o padrão).
+	Difficulty      string         // Dificuldade usada nos quebra-cabeças do dia.
+	Topics          []string       // Tópicos em rodízio: cada data usa um deles.
+	Location        *time.Location // Fuso horário padrão que define a "data de hoje".
+	RefreshInterval time.Duration  // Intervalo do agendador; 0 desativa a geração antecipada.
+}
+
+// LoadConfig lê a configuração das variáveis de ambiente, aplica os valores padrão
+// e valida as combinações obrigatórias.
+func LoadConfig() (*Config, error) {
+	cfg := &Config{
+		Port: getEnv("PORT", "8080"),
+		Gemini: GeminiConfig{
+			APIKeys:      getEnvList("GEMINI_API_KEYS", os.Getenv("GEMINI_API_KEY")),
+			KeySelection: strings.ToLower(getEnv("GEMINI_KEY_SELECTION", KeySelectionRoundRobin)),
+		},
+		CacheBackend: strings.ToLower(getEnv("CACHE_BACKEND", CacheBackendPostgres)),
+		DatabaseURL:  os.Getenv("DATABASE_URL"),
+		SQLitePath:   getEnv("SQLITE_PATH", "puzzle_cache.db"),
+		AdminToken:   os.Getenv("ADMIN_TOKEN"),
+		PromptsDir:   os.Getenv("PROMPTS_DIR"),
+		GRPCPort:     os.Getenv("GRPC_PORT"),
+		Webhooks: WebhookConfig{
+			Secret: os.Getenv("WEBHOOK_SECRET"),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", ""),
+			AllowedMethods: getEnvList("CORS_ALLOWED_METHODS", "GET,POST,OPTIONS"),
+			AllowedHeaders: getEnvList("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,Idempotency-Key"),
+			ExposedHeaders: getEnvList("CORS_EXPOSED_HEADERS", "ETag,Location,Idempotent-Replayed"),
+		},
+		Daily: DailyConfig{
+			GameTypes:  getEnvList("DAILY_GAME_TYPES", "crossword,wordsearch"),
+			Languages:  getEnvList("DAILY_LANGUAGES", "pt"),
+			Difficulty: strings.ToLower(getEnv("DAILY_DIFFICULTY", "medium")),
+			Topics:     getEnvList("DAILY_TOPICS", "animals,nature,science,history,geography,food,sports,music"),
+		},
+	}
+
+	var err error
+	if cfg.Gemini.MaxAttempts, err = getEnvInt("GEMINI_MAX_ATTEMPTS", 3); err != nil {
+		return nil, err
+	}
+	if cfg.Gemini.MaxAttempts < 1 {
+		return nil, fmt.Errorf("GEMINI_MAX_ATTEMPTS deve ser pelo menos 1")
+	}
+	if cfg.Gemini.Models, err = loadGeminiModels(); err != nil {
+		return nil, err
+	}
+	if err := loadUpstreamHTTPConfig(&cfg.Gemini.HTTP); err != nil {
+		return nil, err
+	}
+	if cfg.Gemini.KeyCooldown, err = getEnvDuration("GEMINI_KEY_COOLDOWN", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.CORS.MaxAge, err = getEnvDuration("CORS_MAX_AGE", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.CORS.AllowCredentials, err = getEnvBool("CORS_ALLOW_CREDENTIALS", false); err != nil {
+		return nil, err
+	}
+	if cfg.CORS.AllowCredentials && containsString(cfg.CORS.AllowedOrigins, "*") {
+		return nil, fmt.Errorf("CORS_ALLOW_CREDENTIALS não pode ser usado com CORS_ALLOWED_ORIGINS=*; liste as origens permitidas")
+	}
+	if cfg.Jobs.MaxConcurrent, err = getEnvInt("JOBS_MAX_CONCURRENT", 4); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.MaxConcurrent < 1 {
+		return nil, fmt.Errorf("JOBS_MAX_CONCURRENT deve ser pelo menos 1")
+	}
+	if cfg.Jobs.MaxPending, err = getEnvInt("JOBS_MAX_PENDING", 100); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.MaxPending < cfg.Jobs.MaxConcurrent {
+		return nil, fmt.Errorf("JOBS_MAX_PENDING deve ser pelo menos JOBS_MAX_CONCURRENT (%d)", cfg.Jobs.MaxConcurrent)
+	}
+	if cfg.Jobs.Retention, err = getEnvDuration("JOBS_RETENTION", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.Webhooks.MaxAttempts, err = getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5); err != nil {
+		return nil, err
+	}
+	if cfg.Webhooks.MaxAttempts < 1 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS deve ser pelo menos 1")
+	}
+	if cfg.Webhooks.InitialBackoff, err = getEnvDuration("WEBHOOK_INITIAL_BACKOFF", 2*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Webhooks.Timeout, err = getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Webhooks.AllowPrivate, err = getEnvBool("WEBHOOK_ALLOW_PRIVATE", false); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPCache.Compression, err = getEnvBool("HTTP_COMPRESSION", true); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPCache.CompressionMinSize, err = getEnvInt("HTTP_COMPRESSION_MIN_SIZE", 1024); err != nil {
+		return nil, err
+	}
+	cfg.HTTPCache.CacheControl = loadCacheControl()
+	if cfg.IdempotencyRetention, err = getEnvDuration("IDEMPOTENCY_RETENTION", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.Daily.Location, err = time.LoadLocation(getEnv("DAILY_TIMEZONE", "UTC")); err != nil {
+		return nil, fmt.Errorf("DAILY_TIMEZONE inválido: %w", err)
+	}
+	if cfg.Daily.RefreshInterval, err = getEnvDuration("DAILY_REFRESH_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if len(cfg.Daily.GameTypes) == 0 || len(cfg.Daily.Languages) == 0 || len(cfg.Daily.Topics) == 0 {
+		return nil, fmt.Errorf("DAILY_GAME_TYPES, DAILY_LANGUAGES e DAILY_TOPICS não podem ser vazios")
+	}
+
+	for _, gameType := range cfg.Daily.GameTypes {
+		if _, ok := lookupGameType(gameType); !ok {
+			return nil, fmt.Errorf("DAILY_GAME_TYPES contém um gameType não suportado %q. Disponíveis: %s", gameType, strings.Join(gameTypeNames(), ", "))
+		}
+		for _, language := range cfg.Daily.Languages {
+			if _, err := gameTypeFor(PuzzleRequest{GameType: gameType, Difficulty: cfg.Daily.Difficulty, Language: language}); err != nil {
+				return nil, fmt.Errorf("DAILY_GAME_TYPES e DAILY_LANGUAGES: %w", err)
+			}
+		}
+	}
+
+	if len(cfg.Gemini.APIKeys) == 0 {
+		return nil, fmt.Errorf("variável de ambiente GEMINI_API_KEY não definida. Por favor, forneça sua chave da API Gemini")
+	}
+	for _, key := range cfg.Gemini.APIKeys {
+		if key == "YOUR_GEMINI_API_KEY_HERE" {
+			return nil, fmt.Errorf("GEMINI_API_KEY é o valor padrão. Por favor, defina-a como uma variável de ambiente")
+		}
+	}
+	switch cfg.Gemini.KeySelection {
+	case KeySelectionRoundRobin, KeySelectionLeastThrottled:
+	default:
+		return nil, fmt.Errorf("GEMINI_KEY_SELECTION inválido %q: use %q ou %q", cfg.Gemini.KeySelection, KeySelectionRoundRobin, KeySelectionLeastThrottled)
+	}
+
+	switch cfg.CacheBackend {
+	case CacheBackendPostgres:
+		if cfg.DatabaseURL == "" {
+			return nil, fmt.Errorf("variável de ambiente DATABASE_URL não definida. Por favor, forneça sua string de conexão PostgreSQL")
+		}
+	case CacheBackendSQLite, CacheBackendMemory:
+	default:
+		return nil, fmt.Errorf("CACHE_BACKEND inválido %q: use %q, %q ou %q", cfg.CacheBackend, CacheBackendPostgres, CacheBackendSQLite, CacheBackendMemory)
+	}
+
+	return cfg, nil
+}
+
+// loadUpstreamHTTPConfig lê os timeouts, o pool de conexões e o proxy de saída das chamadas à API Gemini.
+// A geração de um quebra-cabeça pode levar dezenas de segundos, por isso os timeouts de resposta são longos.
+func loadUpstreamHTTPConfig(c *UpstreamHTTPConfig) error {
+	durations := []struct {
+		key      string
+		fallback time.Duration
+		dst      *time.Duration
+	}{
+		{"GEMINI_HTTP_TIMEOUT", 2 * time.Minute, &c.Timeout},
+		{"GEMINI_DIAL_TIMEOUT", 10 * time.Second, &c.DialTimeout},
+		{"GEMINI_TLS_TIMEOUT", 10 * time.Second, &c.TLSHandshakeTimeout},
+		{"GEMINI_RESPONSE_HEADER_TIMEOUT", 90 * time.Second, &c.ResponseHeaderTimeout},
+		{"GEMINI_IDLE_CONN_TIMEOUT", 90 * time.Second, &c.IdleConnTimeout},
+	}
+	for _, d := range durations {
+		v, err := getEnvDuration(d.key, d.fallback)
+		if err != nil {
+			return err
+		}
+		*d.dst = v
+	}
+
+	var err error
+	if c.MaxIdleConnsPerHost, err = getEnvInt("GEMINI_MAX_IDLE_CONNS_PER_HOST", 16); err != nil {
+		return err
+	}
+	if v := os.Getenv("GEMINI_PROXY_URL"); v != "" {
+		if c.ProxyURL, err = url.Parse(v); err != nil || c.ProxyURL.Host == "" {
+			return fmt.Errorf("GEMINI_PROXY_URL inválido %q: use uma URL como http://proxy:3128", v)
+		}
+	}
+	return nil
+}
+
+// loadGeminiModels lê GEMINI_MODELS e as variáveis GEMINI_MODELS_<GAMETYPE> e GEMINI_MODELS_<GAMETYPE>_<DIFFICULTY>,
+// cada uma com uma lista de modelos separados por vírgula (ex: GEMINI_MODELS_CROSSWORD_HARD="gemini-2.5-pro,gemini-2.0-flash").
+// Sufixos que não correspondem a um tipo de jogo gerado pelo Gemini e a uma dificuldade válida são recusados.
+func loadGeminiModels() (ModelChains, error) {
+	models := ModelChains{}
+	if list := getEnvList("GEMINI_MODELS", ""); len(list) > 0 {
+		models[""] = list
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		suffix, ok := strings.CutPrefix(name, "GEMINI_MODELS_")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		gameType, difficulty, hasDifficulty := strings.Cut(strings.ToLower(suffix), "_")
+		// Tipos gerados localmente (como o sudoku) não chamam o Gemini, então uma cadeia para eles nunca seria usada.
+		if gt, ok := lookupGameType(gameType); !ok || gt.isLocal() {
+			return nil, fmt.Errorf("%s: %q não é um gameType gerado pelo Gemini. Disponíveis: %s", name, gameType, strings.Join(geminiGameTypeNames(), ", "))
+		}
+		key := gameType
+		if hasDifficulty {
+			switch difficulty {
+			case "easy", "medium", "hard":
+			default:
+				return nil, fmt.Errorf("%s: dificuldade inválida %q: use easy, medium ou hard", name, difficulty)
+			}
+			key += "/" + difficulty
+		}
+		models[key] = getEnvList(name, "")
+	}
+	return models, nil
+}
+
+// loadCacheControl lê as variáveis CACHE_CONTROL_<OPERATIONID> (ex: CACHE_CONTROL_GETPUZZLE="public, max-age=3600"),
+// que substituem o Cache-Control padrão do endpoint. Os nomes são validados em routes, que conhece os endpoints.
+func loadCacheControl() map[string]string {
+	cacheControl := map[string]string{}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		operation, ok := strings.CutPrefix(name, "CACHE_CONTROL_")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		cacheControl[strings.ToLower(operation)] = strings.TrimSpace(value)
+	}
+	return cacheControl
+}
+
+// getEnv retorna o valor da variável de ambiente ou o valor padrão se ela estiver vazia.
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getEnvList lê uma lista separada por vírgulas, ignorando itens vazios e espaços.
+func getEnvList(key, fallback string) []string {
+	var items []string
+	for _, item := range strings.Split(getEnv(key, fallback), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvInt lê um número inteiro não negativo.
+func getEnvInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s inválido %q: use um número inteiro", key, v)
+	}
+	return n, nil
+}
+
+// getEnvBool lê um valor booleano no formato de strconv.ParseBool (ex: "true", "false", "1", "0").
+func getEnvBool(key string, fallback bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s inválido %q: use true ou false", key, v)
+	}
+	return b, nil
+}
+
+// getEnvDuration lê uma duração no formato de time.ParseDuration (ex: "30s", "1h").
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s inválido %q: use uma duração como 30s ou 1h", key, v)
+	}
+	return d, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadGeminiModels(t *testing.T) {
+	t.Setenv("GEMINI_MODELS", "gemini-2.0-flash")
+	t.Setenv("GEMINI_MODELS_CROSSWORD", "gemini-2.5-flash, gemini-2.0-flash")
+	t.Setenv("GEMINI_MODELS_QUIZ_HARD", "gemini-2.5-pro")
+	t.Setenv("GEMINI_MODELS_WORDSEARCH", " ") // Valores vazios são ignorados.
+
+	models, err := loadGeminiModels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ModelChains{
+		"":          {"gemini-2.0-flash"},
+		"crossword": {"gemini-2.5-flash", "gemini-2.0-flash"},
+		"quiz/hard": {"gemini-2.5-pro"},
+	}
+	if len(models) != len(want) {
+		t.Errorf("loadGeminiModels = %v, quer %v", models, want)
+	}
+	for key, chain := range want {
+		if !slices.Equal(models[key], chain) {
+			t.Errorf("models[%q] = %v, quer %v", key, models[key], chain)
+		}
+	}
+}
+
+func TestLoadGeminiModelsRejectsUnknownSuffix(t *testing.T) {
+	tests := []struct {
+		name, problem string
+	}{
+		{"GEMINI_MODELS_CROSWORD", `"crosword" não é um gameType gerado pelo Gemini`},
+		{"GEMINI_MODELS_SUDOKU", `"sudoku" não é um gameType gerado pelo Gemini`}, // Gerado localmente, sem o Gemini
+		{"GEMINI_MODELS_SUDOKU_HARD", `"sudoku" não é um gameType`},
+		{"GEMINI_MODELS_QUIZ_EXTREME", `dificuldade inválida "extreme"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, "gemini-2.5-pro")
+			_, err := loadGeminiModels()
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Fatalf("loadGeminiModels = %v, quer erro com %q", err, tt.problem)
+			}
+			if _, available, _ := strings.Cut(err.Error(), "Disponíveis:"); strings.Contains(available, "sudoku") {
+				t.Errorf("%v: a lista de disponíveis inclui o sudoku, que não usa o Gemini", err)
+			}
+		})
+	}
+}
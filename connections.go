@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// O Gemini monta os grupos; o proxy os valida e embaralha as palavras.
+func init() {
+	RegisterGameType(GameTypeSpec[ConnectionsData]{
+		Name:        "connections",
+		Description: "connections grouping puzzle",
+		DataField:   "connectionsData",
+		Prompt:      "connections",
+		Validate: func(data *ConnectionsData, _ PuzzleRequest) error {
+			return validateConnectionsData(data)
+		},
+		PostProcess: func(data *ConnectionsData, _ PuzzleRequest) error {
+			shuffleConnections(data)
+			return nil
+		},
+	})
+}
+
+const (
+	connectionsGroups        = 4 // Quantidade de grupos do quebra-cabeça
+	connectionsWordsPerGroup = 4 // Quantidade de palavras em cada grupo
+)
+
+// connectionsMinLabelToken é o tamanho mínimo de uma palavra do rótulo para ser considerada um vazamento.
+// Palavras curtas ("de", "the", "com") aparecem por acaso em muitas palavras e não revelam o grupo.
+const connectionsMinLabelToken = 4
+
+// shuffleConnections ordena os grupos por dificuldade e embaralha as 16 palavras exibidas ao jogador.
+func shuffleConnections(data *ConnectionsData) {
+	groups := data.Groups
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Level < groups[j].Level })
+	words := make([]string, 0, connectionsGroups*connectionsWordsPerGroup)
+	for _, g := range groups {
+		words = append(words, g.Words...)
+	}
+	rand.Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+	data.Words = words
+}
+
+// validateConnectionsData verifica os grupos: exatamente 4 grupos com rótulo e 4 palavras cada, níveis de
+// dificuldade 1 a 4 sem repetição, as 16 palavras distintas (portanto cada uma em um único grupo) e nenhuma
+// palavra contendo o rótulo do próprio grupo ou uma palavra significativa dele. As palavras são normalizadas
+// para maiúsculas.
+func validateConnectionsData(data *ConnectionsData) error {
+	if len(data.Groups) != connectionsGroups {
+		return fmt.Errorf("são necessários %d grupos, recebidos %d", connectionsGroups, len(data.Groups))
+	}
+
+	levels := map[int]bool{}
+	seen := map[string]int{} // Palavra normalizada -> grupo em que apareceu
+	for i := range data.Groups {
+		g := &data.Groups[i]
+		g.Label = strings.TrimSpace(g.Label)
+		if g.Label == "" {
+			return fmt.Errorf("grupo %d: rótulo vazio", i+1)
+		}
+		if g.Level < 1 || g.Level > connectionsGroups {
+			return fmt.Errorf("grupo %q: nível %d fora do intervalo de 1 a %d", g.Label, g.Level, connectionsGroups)
+		}
+		if levels[g.Level] {
+			return fmt.Errorf("grupo %q: nível %d repetido", g.Label, g.Level)
+		}
+		levels[g.Level] = true
+		if len(g.Words) != connectionsWordsPerGroup {
+			return fmt.Errorf("grupo %q: são necessárias %d palavras, recebidas %d", g.Label, connectionsWordsPerGroup, len(g.Words))
+		}
+
+		label := foldConnectionsText(g.Label)
+		for j, word := range g.Words {
+			word = strings.ToUpper(strings.Join(strings.Fields(word), " "))
+			if word == "" {
+				return fmt.Errorf("grupo %q: palavra %d vazia", g.Label, j+1)
+			}
+			g.Words[j] = word
+
+			key := foldConnectionsText(word)
+			if prev, ok := seen[key]; ok {
+				if prev == i {
+					return fmt.Errorf("grupo %q: palavra %q repetida", g.Label, word)
+				}
+				return fmt.Errorf("palavra %q aparece nos grupos %q e %q", word, data.Groups[prev].Label, g.Label)
+			}
+			seen[key] = i
+
+			if leak := connectionsLabelLeak(label, key); leak != "" {
+				return fmt.Errorf("grupo %q: a palavra %q revela o rótulo (%q)", g.Label, word, leak)
+			}
+		}
+	}
+	return nil
+}
+
+// connectionsLabelLeak retorna o trecho do rótulo contido na palavra (ambos já normalizados), ou "" se
+// a palavra não revela o rótulo.
+func connectionsLabelLeak(label, word string) string {
+	if strings.Contains(word, label) {
+		return label
+	}
+	for _, token := range strings.Fields(label) {
+		if len([]rune(token)) >= connectionsMinLabelToken && strings.Contains(word, token) {
+			return token
+		}
+	}
+	return ""
+}
+
+// foldConnectionsText normaliza um texto para comparação: maiúsculas, sem acentos e apenas letras e dígitos,
+// com qualquer outro caractere tratado como separador.
+func foldConnectionsText(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if folded, ok := latinFold[r]; ok {
+			b.WriteString(folded)
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testConnections retorna um quebra-cabeça de conexões válido, novo a cada chamada.
+func testConnections() *ConnectionsData {
+	return &ConnectionsData{Groups: []ConnectionsGroup{
+		{Label: "Felinos", Level: 1, Words: []string{"gato", "LEÃO", "Tigre", "ONÇA"}},
+		{Label: "Aves", Level: 2, Words: []string{"PATO", "GALO", "POMBO", "CORUJA"}},
+		{Label: "Peixes de água doce", Level: 3, Words: []string{"TILÁPIA", "PIRANHA", "DOURADO", "TAMBAQUI"}},
+		{Label: "Insetos", Level: 4, Words: []string{"FORMIGA", "ABELHA", "  mosca ", "GRILO"}},
+	}}
+}
+
+func TestValidateConnectionsData(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(data *ConnectionsData)
+		problem string // Trecho esperado no erro; vazio quando o quebra-cabeça é válido
+	}{
+		{name: "válido", change: func(*ConnectionsData) {}},
+		{name: "níveis fora de ordem", change: func(d *ConnectionsData) { d.Groups[0].Level, d.Groups[3].Level = 4, 1 }},
+		{name: "rótulo com palavra curta contida", change: func(d *ConnectionsData) { d.Groups[2].Label = "Peixes de rio" }}, // "DE" não é vazamento
+		{name: "três grupos", change: func(d *ConnectionsData) { d.Groups = d.Groups[:3] }, problem: "são necessários 4 grupos, recebidos 3"},
+		{name: "cinco grupos", change: func(d *ConnectionsData) { d.Groups = append(d.Groups, d.Groups[0]) }, problem: "recebidos 5"},
+		{name: "três palavras", change: func(d *ConnectionsData) { d.Groups[1].Words = d.Groups[1].Words[:3] }, problem: `grupo "Aves": são necessárias 4 palavras, recebidas 3`},
+		{name: "cinco palavras", change: func(d *ConnectionsData) { d.Groups[1].Words = append(d.Groups[1].Words, "PERU") }, problem: "recebidas 5"},
+		{name: "rótulo vazio", change: func(d *ConnectionsData) { d.Groups[2].Label = " " }, problem: "grupo 3: rótulo vazio"},
+		{name: "nível zero", change: func(d *ConnectionsData) { d.Groups[0].Level = 0 }, problem: "nível 0 fora do intervalo"},
+		{name: "nível cinco", change: func(d *ConnectionsData) { d.Groups[3].Level = 5 }, problem: "nível 5 fora do intervalo"},
+		{name: "nível repetido", change: func(d *ConnectionsData) { d.Groups[3].Level = 2 }, problem: `grupo "Insetos": nível 2 repetido`},
+		{name: "palavra vazia", change: func(d *ConnectionsData) { d.Groups[0].Words[2] = "  " }, problem: "palavra 3 vazia"},
+		{name: "palavra em dois grupos", change: func(d *ConnectionsData) { d.Groups[3].Words[0] = "Galo" }, problem: `palavra "GALO" aparece nos grupos "Aves" e "Insetos"`},
+		{name: "repetida sem acento", change: func(d *ConnectionsData) { d.Groups[1].Words[0] = "LEAO" }, problem: `aparece nos grupos "Felinos" e "Aves"`},
+		{name: "repetida no mesmo grupo", change: func(d *ConnectionsData) { d.Groups[0].Words[3] = "Gato" }, problem: `grupo "Felinos": palavra "GATO" repetida`},
+		{name: "rótulo na palavra", change: func(d *ConnectionsData) { d.Groups[1].Words[3] = "AVES DE RAPINA" }, problem: `a palavra "AVES DE RAPINA" revela o rótulo`},
+		{name: "palavra do rótulo sem acento", change: func(d *ConnectionsData) { d.Groups[2].Words[0] = "AGUA VIVA" }, problem: `revela o rótulo ("AGUA")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testConnections()
+			tt.change(data)
+			err := validateConnectionsData(data)
+			if tt.problem != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.problem) {
+					t.Errorf("validateConnectionsData = %v, quer erro com %q", err, tt.problem)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateConnectionsData = %v, quer nil", err)
+			}
+			// As palavras válidas são normalizadas para maiúsculas, com os espaços colapsados.
+			if got := data.Groups[3].Words[2]; got != "MOSCA" {
+				t.Errorf("palavra normalizada = %q, quer MOSCA", got)
+			}
+		})
+	}
+}
+
+func TestShuffleConnections(t *testing.T) {
+	data := testConnections()
+	data.Groups[0].Level, data.Groups[3].Level = 4, 1
+	if err := validateConnectionsData(data); err != nil {
+		t.Fatal(err)
+	}
+	shuffleConnections(data)
+
+	var want []string
+	for i, g := range data.Groups {
+		if g.Level != i+1 {
+			t.Errorf("grupo %d com nível %d, quer os grupos em ordem de dificuldade", i, g.Level)
+		}
+		want = append(want, g.Words...)
+	}
+	got := slices.Clone(data.Words)
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("words = %v, quer as 16 palavras dos grupos", data.Words)
+	}
+}
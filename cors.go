@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsMiddleware adiciona os cabeçalhos CORS às respostas dos endpoints públicos e responde aos preflights
+// (OPTIONS com Access-Control-Request-Method) sem chamar o manipulador. Os endpoints /admin não recebem
+// cabeçalhos CORS, pois não devem ser chamados por navegadores. Fora os cabeçalhos simples (como
+// Content-Type), o navegador só entrega à página os listados em Access-Control-Expose-Headers.
+func corsMiddleware(cfg CORSConfig, next http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return next
+	}
+	allowAll := containsString(cfg.AllowedOrigins, "*")
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Com uma lista de origens, os cabeçalhos dependem de Origin, inclusive da sua ausência: sem Vary,
+		// um cache compartilhado poderia guardar uma resposta sem CORS (ver Cache-Control em http_cache.go)
+		// e entregá-la a um navegador de uma origem permitida. Com "*", os cabeçalhos são sempre os mesmos.
+		h := w.Header()
+		origin := r.Header.Get("Origin")
+		if !allowAll {
+			h.Add("Vary", "Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		allowed := allowAll || originAllowed(cfg.AllowedOrigins, origin)
+		if allowed {
+			if allowAll {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if exposed != "" {
+				h.Set("Access-Control-Expose-Headers", exposed)
+			}
+		}
+
+		// Preflight: responde diretamente, pois o roteador rejeitaria OPTIONS com 405.
+		if r.Method == http.MethodOptions && origin != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			if allowed {
+				h.Set("Access-Control-Allow-Methods", methods)
+				h.Set("Access-Control-Allow-Headers", headers)
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// originAllowed informa se a origem está na lista, sem diferenciar maiúsculas de minúsculas.
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
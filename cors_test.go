@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSExposeHeaders(t *testing.T) {
+	defaults := []string{"ETag", "Location", "Idempotent-Replayed"}
+	tests := []struct {
+		name    string
+		exposed []string
+		origin  string
+		path    string
+		want    string
+	}{
+		{"origem permitida", defaults, "https://app.example.com", "/daily", "ETag, Location, Idempotent-Replayed"},
+		{"lista configurada", []string{"ETag"}, "https://app.example.com", "/daily", "ETag"},
+		{"lista vazia", nil, "https://app.example.com", "/daily", ""},
+		{"origem não permitida", defaults, "https://evil.example.com", "/daily", ""},
+		{"sem Origin", defaults, "", "/daily", ""},
+		{"endpoint admin", defaults, "https://app.example.com", "/admin/puzzles", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, ExposedHeaders: tt.exposed}
+			handler := corsMiddleware(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `W/"abc"`)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if got := rec.Header().Get("Access-Control-Expose-Headers"); got != tt.want {
+				t.Errorf("Access-Control-Expose-Headers = %q, quer %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testCORSConfig permite uma única origem, com os métodos e cabeçalhos padrão.
+var testCORSConfig = CORSConfig{
+	AllowedOrigins: []string{"https://app.example.com"},
+	AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+	AllowedHeaders: []string{"Content-Type", "Idempotency-Key"},
+	MaxAge:         10 * time.Minute,
+}
+
+func TestCORSPreflight(t *testing.T) {
+	tests := []struct {
+		name        string
+		origin      string
+		path        string
+		wantStatus  int
+		wantAllowed bool
+	}{
+		{"origem permitida", "https://app.example.com", "/generate-puzzle", http.StatusNoContent, true},
+		{"origem não permitida", "https://evil.example.com", "/generate-puzzle", http.StatusNoContent, false},
+		{"endpoint admin", "https://app.example.com", "/admin/puzzles", http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := corsMiddleware(testCORSConfig, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusMethodNotAllowed) // Como o ServeMux responde a OPTIONS.
+			}))
+
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, quer %d", rec.Code, tt.wantStatus)
+			}
+			if isAdmin := tt.path != "/generate-puzzle"; called != isAdmin {
+				t.Errorf("manipulador chamado = %v, quer %v", called, isAdmin)
+			}
+			want := map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+				"Access-Control-Allow-Headers": "",
+				"Access-Control-Max-Age":       "",
+			}
+			if tt.wantAllowed {
+				want = map[string]string{
+					"Access-Control-Allow-Origin":  "https://app.example.com",
+					"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+					"Access-Control-Allow-Headers": "Content-Type, Idempotency-Key",
+					"Access-Control-Max-Age":       "600",
+				}
+			}
+			for header, value := range want {
+				if got := rec.Header().Get(header); got != value {
+					t.Errorf("%s = %q, quer %q", header, got, value)
+				}
+			}
+		})
+	}
+}
+
+// Toda resposta pública depende de Origin quando há uma lista de origens, inclusive as de requisições
+// sem Origin, que um cache compartilhado poderia entregar a um navegador.
+func TestCORSVaryOrigin(t *testing.T) {
+	tests := []struct {
+		name      string
+		origins   []string
+		origin    string
+		path      string
+		wantVary  bool
+		wantAllow string
+	}{
+		{"sem Origin", []string{"https://app.example.com"}, "", "/daily", true, ""},
+		{"origem permitida", []string{"https://app.example.com"}, "https://app.example.com", "/daily", true, "https://app.example.com"},
+		{"origem não permitida", []string{"https://app.example.com"}, "https://evil.example.com", "/daily", true, ""},
+		{"admin", []string{"https://app.example.com"}, "https://app.example.com", "/admin/puzzles", false, ""},
+		{"qualquer origem, sem Origin", []string{"*"}, "", "/daily", false, "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := corsMiddleware(CORSConfig{AllowedOrigins: tt.origins}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "public, max-age=300")
+			}))
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if vary := containsString(rec.Header().Values("Vary"), "Origin"); vary != tt.wantVary {
+				t.Errorf("Vary: Origin = %v, quer %v (Vary = %q)", vary, tt.wantVary, rec.Header().Values("Vary"))
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, quer %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
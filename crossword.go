@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Palavras cruzadas são geradas inteiramente pelo Gemini, incluindo as posições das palavras na grade;
+// o proxy verifica se elas cabem na grade e se concordam nos cruzamentos.
+func init() {
+	RegisterGameType(GameTypeSpec[CrosswordData]{
+		Name:        "crossword",
+		Description: "crossword puzzle",
+		DataField:   "crosswordData",
+		Prompt:      "crossword",
+		Validate: func(data *CrosswordData, _ PuzzleRequest) error {
+			return validateCrosswordData(data)
+		},
+	})
+}
+
+// validateCrosswordData verifica as palavras cruzadas: grade com dimensões positivas, cada palavra só com
+// letras e inteiramente dentro da grade a partir de startRow/startCol na sua direção, e cada célula
+// compartilhada por duas palavras com a mesma letra em ambas. As palavras são normalizadas para maiúsculas.
+func validateCrosswordData(data *CrosswordData) error {
+	rows, cols := data.GridSize.Rows, data.GridSize.Cols
+	if rows <= 0 || cols <= 0 {
+		return fmt.Errorf("gridSize inválido: %dx%d", rows, cols)
+	}
+	if len(data.Words) == 0 {
+		return fmt.Errorf("nenhuma palavra na grade")
+	}
+
+	type cell struct{ row, col int }
+	type letter struct {
+		r    rune
+		word string
+	}
+	grid := map[cell]letter{}
+	for i := range data.Words {
+		w := &data.Words[i]
+		w.Word = strings.ToUpper(strings.TrimSpace(w.Word))
+		letters := []rune(w.Word)
+		if len(letters) == 0 {
+			return fmt.Errorf("palavra %d vazia", i+1)
+		}
+		for _, r := range letters {
+			if !unicode.IsLetter(r) {
+				return fmt.Errorf("palavra %q: contém %q, que não é uma letra", w.Word, r)
+			}
+		}
+
+		dRow, dCol := 0, 1
+		if w.Direction == "down" {
+			dRow, dCol = 1, 0
+		}
+		endRow, endCol := w.StartRow+dRow*(len(letters)-1), w.StartCol+dCol*(len(letters)-1)
+		if w.StartRow < 0 || w.StartCol < 0 || endRow >= rows || endCol >= cols {
+			return fmt.Errorf("palavra %q (%s, início em linha %d, coluna %d) não cabe na grade %dx%d",
+				w.Word, w.Direction, w.StartRow, w.StartCol, rows, cols)
+		}
+
+		for j, r := range letters {
+			c := cell{w.StartRow + dRow*j, w.StartCol + dCol*j}
+			if prev, ok := grid[c]; ok && prev.r != r {
+				return fmt.Errorf("palavras %q e %q se cruzam na linha %d, coluna %d com letras diferentes (%c e %c)",
+					prev.word, w.Word, c.row, c.col, prev.r, r)
+			}
+			grid[c] = letter{r, w.Word}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode"
+)
+
+// O Gemini escolhe o texto; o proxy aplica a cifra.
+func init() {
+	RegisterGameType(GameTypeSpec[CryptogramData]{
+		Name:        "cryptogram",
+		Description: "cryptogram puzzle",
+		DataField:   "cryptogramData",
+		Prompt:      "cryptogram",
+		PostProcess: func(data *CryptogramData, req PuzzleRequest) error {
+			if err := encryptCryptogram(data, req.Language, strings.ToLower(req.Difficulty)); err != nil {
+				return invalidPuzzle(err) // O texto escolhido pelo Gemini não serve; outro texto pode servir.
+			}
+			return nil
+		},
+	})
+}
+
+// cryptogramMinLetters é a quantidade mínima de letras para que o criptograma seja resolvível.
+const cryptogramMinLetters = 20
+
+// cryptogramGivenLetters define quantas letras são reveladas de início em cada dificuldade.
+var cryptogramGivenLetters = map[string]int{
+	"easy":   4,
+	"medium": 2,
+	"hard":   0,
+}
+
+// cryptogramAlphabet descreve como o texto de um idioma é normalizado antes de ser cifrado.
+type cryptogramAlphabet struct {
+	letters []rune          // Letras cifradas, em ordem
+	fold    map[rune]string // Letras acentuadas (em maiúsculas) e sua forma sem acento
+}
+
+// latinFold remove os acentos das letras latinas mais comuns. Os criptogramas tradicionalmente
+// cifram apenas as letras base, então "AÇÃO" é cifrado como "ACAO".
+var latinFold = map[rune]string{
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Ā': "A",
+	'Ç': "C", 'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ñ': "N",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ý': "Y", 'Ÿ': "Y",
+	'Æ': "AE", 'Œ': "OE", 'ß': "SS", 'ẞ': "SS",
+}
+
+// cryptogramAlphabetFor retorna o alfabeto do idioma. Espanhol mantém o Ñ como letra própria e
+// alemão escreve os tremas como AE, OE e UE; os demais idiomas latinos apenas removem os acentos.
+func cryptogramAlphabetFor(language string) cryptogramAlphabet {
+	letters := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	fold := make(map[rune]string, len(latinFold))
+	for k, v := range latinFold {
+		fold[k] = v
+	}
+
+	switch languageCode(language) {
+	case "es":
+		letters = []rune("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+		delete(fold, 'Ñ')
+	case "de":
+		fold['Ä'], fold['Ö'], fold['Ü'] = "AE", "OE", "UE"
+	}
+	return cryptogramAlphabet{letters: letters, fold: fold}
+}
+
+// normalize converte o texto para maiúsculas e remove os acentos conforme o alfabeto.
+// Retorna erro se restar alguma letra fora do alfabeto (por exemplo, de outro sistema de escrita).
+func (a cryptogramAlphabet) normalize(text string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if folded, ok := a.fold[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		if unicode.IsLetter(r) && !a.contains(r) {
+			return "", fmt.Errorf("letra %q fora do alfabeto do idioma", r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), nil
+}
+
+// contains informa se a letra pertence ao alfabeto.
+func (a cryptogramAlphabet) contains(r rune) bool {
+	for _, l := range a.letters {
+		if l == r {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptCryptogram aplica uma cifra de substituição aleatória ao texto, sem nenhuma letra mapeada
+// para si mesma, e revela algumas letras conforme a dificuldade. Caracteres que não são letras
+// (espaços, pontuação, dígitos) são mantidos.
+func encryptCryptogram(data *CryptogramData, language, difficulty string) error {
+	alphabet := cryptogramAlphabetFor(language)
+	solution, err := alphabet.normalize(strings.TrimSpace(data.Quote))
+	if err != nil {
+		return err
+	}
+
+	// Letras distintas do texto, na ordem em que aparecem.
+	var used []rune
+	seen := map[rune]bool{}
+	letterCount := 0
+	for _, r := range solution {
+		if alphabet.contains(r) {
+			letterCount++
+			if !seen[r] {
+				seen[r] = true
+				used = append(used, r)
+			}
+		}
+	}
+	if letterCount < cryptogramMinLetters {
+		return fmt.Errorf("texto com apenas %d letras, mínimo de %d", letterCount, cryptogramMinLetters)
+	}
+
+	key := cryptogramKey(alphabet.letters)
+	ciphertext := []rune(solution)
+	for i, r := range ciphertext {
+		if c, ok := key[r]; ok {
+			ciphertext[i] = c
+		}
+	}
+
+	// Revela letras aleatórias do texto; nunca todas, para que ainda haja o que resolver.
+	given := map[string]string{}
+	reveal := min(cryptogramGivenLetters[difficulty], len(used)/2)
+	for _, i := range rand.Perm(len(used))[:reveal] {
+		given[string(key[used[i]])] = string(used[i])
+	}
+
+	data.Alphabet = string(alphabet.letters)
+	data.Ciphertext = string(ciphertext)
+	data.Solution = solution
+	data.GivenLetters = given
+	return nil
+}
+
+// cryptogramKey sorteia uma permutação do alfabeto sem pontos fixos (um desarranjo), de modo que
+// nenhuma letra seja cifrada como ela mesma. Cerca de 37% das permutações são desarranjos,
+// então poucas tentativas bastam.
+func cryptogramKey(letters []rune) map[rune]rune {
+	shuffled := append([]rune(nil), letters...)
+	for {
+		rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		fixed := false
+		for i := range letters {
+			if letters[i] == shuffled[i] {
+				fixed = true
+				break
+			}
+		}
+		if !fixed {
+			break
+		}
+	}
+
+	key := make(map[rune]rune, len(letters))
+	for i, l := range letters {
+		key[l] = shuffled[i]
+	}
+	return key
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestEncryptCryptogram(t *testing.T) {
+	tests := []struct {
+		language, quote, solution, alphabet string
+	}{ // No espanhol, só o Ñ é letra própria; os acentos são removidos como nos demais idiomas.
+		{"pt", "A ação é a chave do sucesso, disse o sábio.", "A ACAO E A CHAVE DO SUCESSO, DISSE O SABIO.", "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{"en", "The piñata café opened in 1999, señor!", "THE PINATA CAFE OPENED IN 1999, SENOR!", "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{"es", "El año que viene, señor, será mejor.", "EL AÑO QUE VIENE, SEÑOR, SERA MEJOR.", "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"},
+		{"de", "Schöne Grüße aus München, sagt Bär.", "SCHOENE GRUESSE AUS MUENCHEN, SAGT BAER.", "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+	}
+	for _, tt := range tests {
+		for difficulty, wantGiven := range cryptogramGivenLetters {
+			for i := 0; i < 50; i++ { // A cifra é aleatória.
+				data := &CryptogramData{Quote: tt.quote}
+				if err := encryptCryptogram(data, tt.language, difficulty); err != nil {
+					t.Fatalf("%s/%s: %v", tt.language, difficulty, err)
+				}
+				if data.Solution != tt.solution || data.Alphabet != tt.alphabet {
+					t.Fatalf("%s: solution %q com alfabeto %q, quer %q com %q", tt.language, data.Solution, data.Alphabet, tt.solution, tt.alphabet)
+				}
+
+				plain, cipher := []rune(data.Solution), []rune(data.Ciphertext)
+				if len(plain) != len(cipher) {
+					t.Fatalf("%s: ciphertext %q não corresponde letra a letra a %q", tt.language, data.Ciphertext, data.Solution)
+				}
+				key, inverse := map[rune]rune{}, map[rune]rune{}
+				for j, p := range plain {
+					c := cipher[j]
+					if !unicode.IsLetter(p) {
+						if c != p {
+							t.Fatalf("%s: %q cifrado como %q; só letras são cifradas", tt.language, p, c)
+						}
+						continue
+					}
+					if c == p {
+						t.Fatalf("%s: letra %q cifrada como ela mesma em %q", tt.language, p, data.Ciphertext)
+					}
+					if !strings.ContainsRune(data.Alphabet, c) {
+						t.Fatalf("%s: letra cifrada %q fora do alfabeto %q", tt.language, c, data.Alphabet)
+					}
+					if prev, ok := key[p]; ok && prev != c {
+						t.Fatalf("%s: %q cifrada como %q e %q", tt.language, p, prev, c)
+					}
+					if prev, ok := inverse[c]; ok && prev != p {
+						t.Fatalf("%s: %q e %q cifradas como %q", tt.language, prev, p, c)
+					}
+					key[p], inverse[c] = c, p
+				}
+
+				if want := min(wantGiven, len(key)/2); len(data.GivenLetters) != want {
+					t.Fatalf("%s/%s: %d letras reveladas, quer %d", tt.language, difficulty, len(data.GivenLetters), want)
+				}
+				for c, p := range data.GivenLetters {
+					if inverse[[]rune(c)[0]] != []rune(p)[0] {
+						t.Fatalf("%s/%s: letra revelada %s -> %s não corresponde à cifra", tt.language, difficulty, c, p)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEncryptCryptogramRejectsUnusableText(t *testing.T) {
+	tests := []struct {
+		language, quote, problem string
+	}{
+		{"pt", "Curto demais.", "mínimo de 20"},
+		{"pt", "1234567890 1234567890 1234567890!", "apenas 0 letras"},
+		{"en", "Мир труду, май, мир и дружба народов", "fora do alfabeto"},
+		{"pt", "Ω é a última letra do alfabeto grego", "fora do alfabeto"},
+	}
+	for _, tt := range tests {
+		data := &CryptogramData{Quote: tt.quote}
+		if err := encryptCryptogram(data, tt.language, "easy"); err == nil || !strings.Contains(err.Error(), tt.problem) {
+			t.Errorf("encryptCryptogram(%q) = %v, quer erro com %q", tt.quote, err, tt.problem)
+		}
+	}
+}
+
+func TestCryptogramKeyIsDerangement(t *testing.T) {
+	for _, alphabet := range []string{"ABCDEFGHIJKLMNOPQRSTUVWXYZ", "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"} {
+		letters := []rune(alphabet)
+		for i := 0; i < 1000; i++ {
+			key := cryptogramKey(letters)
+			targets := map[rune]bool{}
+			for _, l := range letters {
+				if key[l] == l {
+					t.Fatalf("%q mapeada para si mesma", l)
+				}
+				targets[key[l]] = true
+			}
+			if len(key) != len(letters) || len(targets) != len(letters) {
+				t.Fatalf("a chave %v não é uma permutação de %s", key, alphabet)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Embute a base de fusos horários; a imagem alpine não a inclui.
+)
+
+// keyedMutex serializa operações por chave, para que requisições simultâneas do mesmo
+// quebra-cabeça do dia não disparem várias chamadas ao Gemini.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock é o mutex de uma chave, com a contagem de quem o está usando.
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+// Lock bloqueia a chave e retorna a função que a libera.
+func (k *keyedMutex) Lock(key string) (unlock func()) {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		k.mu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// dailyHandler é o manipulador de GET /daily?gameType=&language=&tz=.
+// Todos os jogadores recebem o mesmo quebra-cabeça para a mesma data. A data é calculada no fuso
+// horário do parâmetro tz (nome IANA, ex: "America/Sao_Paulo") ou, na sua ausência, em DAILY_TIMEZONE.
+func (s *Server) dailyHandler(w http.ResponseWriter, r *http.Request) {
+	puzzle, ok := s.serveDaily(w, r)
+	if !ok {
+		return
+	}
+	writeCacheable(w, r, puzzle.etag(), puzzle.Data)
+}
+
+// serveDaily interpreta os parâmetros de GET /daily e obtém o quebra-cabeça do dia. Em caso de erro,
+// escreve a resposta de erro e retorna false.
+func (s *Server) serveDaily(w http.ResponseWriter, r *http.Request) (*generatedPuzzle, bool) {
+	query := r.URL.Query()
+
+	gameType, ok := pickDailyOption(query.Get("gameType"), s.daily.GameTypes)
+	if !ok {
+		http.Error(w, "gameType sem quebra-cabeça do dia. Disponíveis: "+strings.Join(s.daily.GameTypes, ", "), http.StatusBadRequest)
+		return nil, false
+	}
+	language, ok := pickDailyOption(query.Get("language"), s.daily.Languages)
+	if !ok {
+		http.Error(w, "language sem quebra-cabeça do dia. Disponíveis: "+strings.Join(s.daily.Languages, ", "), http.StatusBadRequest)
+		return nil, false
+	}
+
+	loc := s.daily.Location
+	if tz := query.Get("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			http.Error(w, "Fuso horário (tz) inválido: "+tz, http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	date := time.Now().In(loc).Format(time.DateOnly)
+
+	puzzle, err := s.dailyPuzzle(r.Context(), gameType, language, date)
+	if err != nil {
+		log.Printf("Erro ao obter o quebra-cabeça do dia %s/%s/%s: %v", date, gameType, language, err)
+		status, _ := generationErrorStatus(err)
+		http.Error(w, "Falha ao obter o quebra-cabeça do dia.", status)
+		return nil, false
+	}
+	return puzzle, true
+}
+
+// pickDailyOption retorna a opção configurada que corresponde ao valor (sem diferenciar maiúsculas),
+// ou a primeira opção quando o valor está vazio.
+func pickDailyOption(value string, options []string) (string, bool) {
+	if value == "" {
+		return options[0], true
+	}
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// dailyPuzzle retorna o quebra-cabeça do dia para o tipo de jogo, idioma e data, gerando-o se necessário.
+// Se ctx for cancelado durante a geração, quem esperava pela mesma data tenta gerar de novo.
+func (s *Server) dailyPuzzle(ctx context.Context, gameType, language, date string) (*generatedPuzzle, error) {
+	// Incluir a data na chave faz com que cada dia tenha seu próprio registro, reaproveitando a tabela
+	// cached_puzzles e o fluxo de geração normal.
+	req := s.dailyRequest(gameType, language, date)
+	entry, err := s.cacheEntry(req, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requisições simultâneas para o mesmo dia esperam a primeira geração e depois leem o cache.
+	unlock := s.dailyLocks.Lock(entry.RequestHash)
+	defer unlock()
+
+	return s.getOrGeneratePuzzle(ctx, req, entry)
+}
+
+// dailyRequest monta os parâmetros do quebra-cabeça do dia. O tópico é escolhido em rodízio
+// a partir da data, então é o mesmo em todas as instâncias do proxy.
+func (s *Server) dailyRequest(gameType, language, date string) PuzzleRequest {
+	topic := s.daily.Topics[0]
+	if day, err := time.Parse(time.DateOnly, date); err == nil {
+		days := int(day.Unix() / int64(24*time.Hour/time.Second))
+		topic = s.daily.Topics[days%len(s.daily.Topics)]
+	}
+	return PuzzleRequest{
+		GameType:   gameType,
+		Difficulty: s.daily.Difficulty,
+		Topics:     []string{topic},
+		Language:   language,
+	}
+}
+
+// runDailyScheduler gera antecipadamente os quebra-cabeças de hoje e de amanhã (no fuso de DAILY_TIMEZONE)
+// para todos os tipos de jogo e idiomas configurados, repetindo a cada RefreshInterval.
+// Gerar também o dia seguinte cobre os clientes em fusos horários à frente do configurado.
+func (s *Server) runDailyScheduler() {
+	if s.daily.RefreshInterval == 0 {
+		log.Println("DAILY_REFRESH_INTERVAL=0; quebra-cabeças do dia serão gerados sob demanda.")
+		return
+	}
+
+	ticker := time.NewTicker(s.daily.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		s.pregenerateDailyPuzzles(time.Now())
+		<-ticker.C
+	}
+}
+
+// pregenerateDailyPuzzles garante que os quebra-cabeças do dia de now e do dia seguinte existam no cache.
+func (s *Server) pregenerateDailyPuzzles(now time.Time) {
+	today := now.In(s.daily.Location)
+	for _, day := range []time.Time{today, today.AddDate(0, 0, 1)} {
+		date := day.Format(time.DateOnly)
+		for _, gameType := range s.daily.GameTypes {
+			for _, language := range s.daily.Languages {
+				if _, err := s.dailyPuzzle(context.Background(), gameType, language, date); err != nil {
+					log.Printf("Agendador: falha ao gerar o quebra-cabeça do dia %s/%s/%s: %v", date, gameType, language, err)
+				}
+			}
+		}
+	}
+}
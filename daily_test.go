@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Mudar o template de prompt ou a cadeia de modelos no meio do dia não pode trocar o quebra-cabeça do
+// dia; nas demais requisições, a mudança deve gerar um novo quebra-cabeça.
+func TestDailyPuzzleKeyIgnoresPromptAndModelChanges(t *testing.T) {
+	prompts, err := LoadPrompts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		store:               NewMemoryStore(),
+		geminiPuzzleService: &GeminiPuzzleService{models: ModelChains{"": {"gemini-a"}}, prompts: prompts},
+		daily:               DailyConfig{Difficulty: "medium", Topics: []string{"animais"}, Location: time.UTC},
+	}
+	const date = "2026-10-18"
+	req := s.dailyRequest("crossword", "pt", date)
+
+	daily, err := s.cacheEntry(req, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := s.cacheEntry(req, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily.ResponseData = []byte(`{"gameType":"crossword"}`)
+	daily.CreatedAt = time.Now().UTC()
+	if err := s.store.SaveCachedPuzzle(daily); err != nil {
+		t.Fatal(err)
+	}
+
+	s.geminiPuzzleService.models = ModelChains{"": {"gemini-b"}}
+	prompts.templates["crossword"].version += "-editado"
+
+	changedDaily, err := s.cacheEntry(req, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changedDaily.RequestHash != daily.RequestHash {
+		t.Errorf("chave do dia mudou com o prompt e o modelo: %s -> %s", daily.RequestHash, changedDaily.RequestHash)
+	}
+	if changedRegular, err := s.cacheEntry(req, ""); err != nil {
+		t.Fatal(err)
+	} else if changedRegular.RequestHash == regular.RequestHash {
+		t.Error("chave de uma requisição comum não mudou com o prompt e o modelo")
+	}
+
+	// O serviço de teste não tem chaves da API: o quebra-cabeça só pode vir do cache.
+	puzzle, err := s.dailyPuzzle(context.Background(), "crossword", "pt", date)
+	if err != nil {
+		t.Fatalf("dailyPuzzle = %v, quer o quebra-cabeça em cache", err)
+	}
+	if !puzzle.Cached || puzzle.PuzzleID != daily.PuzzleID {
+		t.Errorf("dailyPuzzle = %+v, quer o quebra-cabeça %s do cache", puzzle, daily.PuzzleID)
+	}
+}
@@ -2,16 +2,81 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq" // Driver PostgreSQL para database/sql
+	_ "github.com/lib/pq"  // Driver PostgreSQL para database/sql
+	_ "modernc.org/sqlite" // Driver SQLite (Go puro) para database/sql
 )
 
+// sqliteSchema cria as tabelas no SQLite. Espelha schema.sql, usando TEXT no lugar de JSONB e BLOB no lugar de BYTEA.
+const sqliteSchema = `
+	CREATE TABLE IF NOT EXISTS cached_puzzles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		request_hash TEXT UNIQUE NOT NULL,
+		puzzle_id TEXT,
+		puzzle_date TEXT,
+		prompt_version TEXT,
+		model TEXT,
+		schema_version TEXT,
+		request_params TEXT NOT NULL,
+		response_data TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		operation TEXT NOT NULL,
+		idempotency_key TEXT NOT NULL,
+		request_hash TEXT NOT NULL,
+		status_code INTEGER NOT NULL,
+		headers TEXT NOT NULL,
+		body BLOB NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (operation, idempotency_key)
+	);
+	CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys (created_at);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		delivery_id TEXT NOT NULL,
+		job_id TEXT NOT NULL,
+		url TEXT NOT NULL,
+		event TEXT NOT NULL,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER,
+		error TEXT,
+		duration_ms INTEGER NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS webhook_deliveries_job_id_idx ON webhook_deliveries (job_id);
+`
+
+// sqliteMigrations adiciona, em bancos SQLite criados por versões anteriores, as colunas que passaram
+// a existir depois. O SQLite não suporta "ADD COLUMN IF NOT EXISTS", então a coluna é verificada antes.
+var sqliteMigrations = []struct {
+	table, column, definition string
+	backfill                  string // SQL opcional executado após adicionar a coluna
+}{
+	{"cached_puzzles", "puzzle_id", "TEXT", "UPDATE cached_puzzles SET puzzle_id = substr(request_hash, 1, 16) WHERE puzzle_id IS NULL"},
+	{"cached_puzzles", "puzzle_date", "TEXT", ""},
+	{"cached_puzzles", "prompt_version", "TEXT", ""},
+	{"cached_puzzles", "model", "TEXT", ""},
+	{"cached_puzzles", "schema_version", "TEXT", ""},
+}
+
+// sqliteIndexes cria os índices depois das migrações, pois dependem das colunas adicionadas.
+const sqliteIndexes = `
+	CREATE UNIQUE INDEX IF NOT EXISTS cached_puzzles_puzzle_id_idx ON cached_puzzles (puzzle_id);
+`
+
 // DBService lida com todas as operações de banco de dados, especificamente para cache de respostas de quebra-cabeças.
+// A mesma implementação atende PostgreSQL e SQLite; as consultas usam apenas SQL comum aos dois.
 type DBService struct {
-	db *sql.DB // O pool de conexão do banco de dados subjacente
+	db      *sql.DB // O pool de conexão do banco de dados subjacente
+	dialect string  // CacheBackendPostgres ou CacheBackendSQLite
 }
 
 // NewDBService inicializa um novo DBService estabelecendo uma conexão com o banco de dados PostgreSQL.
@@ -29,54 +94,367 @@ func NewDBService(connStr string) (*DBService, error) {
 	}
 
 	log.Println("Conectado com sucesso ao banco de dados PostgreSQL.")
-	return &DBService{db: db}, nil
+	return &DBService{db: db, dialect: CacheBackendPostgres}, nil
 }
 
-// Close fecha a conexão com o banco de dados. É importante adiar esta chamada
-// na função principal para garantir a limpeza adequada dos recursos.
-func (s *DBService) Close() error {
-	log.Println("Fechando conexão com o banco de dados.")
-	return s.db.Close()
+// NewSQLiteService abre (ou cria) o arquivo SQLite em path e garante que a tabela de cache exista.
+// Útil para desenvolvimento local sem um projeto Supabase; use ":memory:" para um banco temporário.
+func NewSQLiteService(path string) (*DBService, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir o banco de dados SQLite: %w", err)
+	}
+	// O SQLite serializa escritas; uma única conexão evita erros de "database is locked"
+	// e mantém o mesmo banco quando path é ":memory:".
+	db.SetMaxOpenConns(1)
+
+	if err = migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("Banco de dados SQLite aberto em %s.", path)
+	return &DBService{db: db, dialect: CacheBackendSQLite}, nil
 }
 
-// GetCachedPuzzle recupera uma resposta de quebra-cabeça em cache do banco de dados usando um hash de requisição.
-// Retorna os dados da resposta em cache como um slice de bytes, se encontrado, ou nil se não encontrado (sql.ErrNoRows).
-// Qualquer outro erro de banco de dados será retornado.
-func (s *DBService) GetCachedPuzzle(requestHash string) ([]byte, error) {
-	var responseData []byte // Variável para armazenar os dados da resposta recuperados
-	query := "SELECT response_data FROM cached_puzzles WHERE request_hash = $1"
+// migrateSQLite cria o schema SQLite e aplica as migrações de colunas e índices.
+func migrateSQLite(db *sql.DB) error {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("falha ao criar o schema SQLite: %w", err)
+	}
 
-	// QueryRow executa uma consulta que deve retornar no máximo uma linha.
-	// Scan copia as colunas da linha correspondente para a variável responseData.
-	err := s.db.QueryRow(query, requestHash).Scan(&responseData)
-	if err == sql.ErrNoRows {
-		return nil, nil // Nenhuma linha encontrada, indicando um cache miss
+	for _, m := range sqliteMigrations {
+		var exists bool
+		query := "SELECT COUNT(*) > 0 FROM pragma_table_info($1) WHERE name = $2"
+		if err := db.QueryRow(query, m.table, m.column).Scan(&exists); err != nil {
+			return fmt.Errorf("falha ao inspecionar a coluna %s.%s: %w", m.table, m.column, err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)); err != nil {
+			return fmt.Errorf("falha ao adicionar a coluna %s.%s: %w", m.table, m.column, err)
+		}
+		if m.backfill != "" {
+			if _, err := db.Exec(m.backfill); err != nil {
+				return fmt.Errorf("falha ao preencher a coluna %s.%s: %w", m.table, m.column, err)
+			}
+		}
+		log.Printf("Migração SQLite aplicada: coluna %s.%s adicionada.", m.table, m.column)
 	}
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter quebra-cabeça em cache para o hash %s: %w", requestHash, err)
+
+	if _, err := db.Exec(sqliteIndexes); err != nil {
+		return fmt.Errorf("falha ao criar os índices SQLite: %w", err)
 	}
-	log.Printf("Cache hit para o hash: %s", requestHash)
-	return responseData, nil
+	return nil
+}
+
+// Close fecha a conexão com o banco de dados. É importante adiar esta chamada
+// na função principal para garantir a limpeza adequada dos recursos.
+func (s *DBService) Close() error {
+	log.Println("Fechando conexão com o banco de dados.")
+	return s.db.Close()
 }
 
 // SaveCachedPuzzle salva uma resposta de quebra-cabeça no cache do banco de dados.
-// Ele recebe o hash da requisição, os parâmetros da requisição original e os dados da resposta do Gemini.
+// Ele recebe o registro com o hash da requisição, os parâmetros da requisição original, os dados da resposta
+// do Gemini, a versão do prompt, o modelo usado, a versão do schema dos dados e, para quebra-cabeças diários, a data. O ID público do quebra-cabeça é derivado do hash,
+// então permanece o mesmo quando o registro é atualizado.
 // Ele usa um UPSERT (ON CONFLICT DO UPDATE) para inserir um novo registro ou atualizar um existente
 // se um registro com o mesmo request_hash já existir.
-func (s *DBService) SaveCachedPuzzle(requestHash string, requestParams []byte, responseData []byte) error {
+func (s *DBService) SaveCachedPuzzle(puzzle *CachedPuzzle) error {
 	query := `
-		INSERT INTO cached_puzzles (request_hash, request_params, response_data, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO cached_puzzles (request_hash, puzzle_id, puzzle_date, prompt_version, model, schema_version, request_params, response_data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (request_hash) DO UPDATE SET
+			puzzle_date = EXCLUDED.puzzle_date,
+			prompt_version = EXCLUDED.prompt_version,
+			model = EXCLUDED.model,
+			schema_version = EXCLUDED.schema_version,
 			request_params = EXCLUDED.request_params,
 			response_data = EXCLUDED.response_data,
 			created_at = EXCLUDED.created_at
 	`
 	// Exec executa uma consulta sem retornar nenhuma linha.
-	_, err := s.db.Exec(query, requestHash, requestParams, responseData, time.Now())
+	// O JSON é enviado como texto: o PostgreSQL o converte para JSONB e o SQLite o mantém consultável pelas funções json_*.
+	_, err := s.db.Exec(query,
+		puzzle.RequestHash,
+		puzzleIDForHash(puzzle.RequestHash),
+		sql.NullString{String: puzzle.PuzzleDate, Valid: puzzle.PuzzleDate != ""},
+		sql.NullString{String: puzzle.PromptVersion, Valid: puzzle.PromptVersion != ""},
+		sql.NullString{String: puzzle.Model, Valid: puzzle.Model != ""},
+		sql.NullString{String: puzzle.SchemaVersion, Valid: puzzle.SchemaVersion != ""},
+		string(puzzle.RequestParams),
+		string(puzzle.ResponseData),
+		createdAtOrNow(puzzle.CreatedAt),
+	)
 	if err != nil {
-		return fmt.Errorf("falha ao salvar quebra-cabeça em cache para o hash %s: %w", requestHash, err)
+		return fmt.Errorf("falha ao salvar quebra-cabeça em cache para o hash %s: %w", puzzle.RequestHash, err)
 	}
-	log.Printf("Cache salvo para o hash: %s", requestHash)
+	log.Printf("Cache salvo para o hash: %s", puzzle.RequestHash)
 	return nil
 }
+
+// GetCachedPuzzleEntry recupera o registro completo (parâmetros, resposta e data) de um hash.
+// Retorna nil se o hash não estiver no cache.
+func (s *DBService) GetCachedPuzzleEntry(requestHash string) (*CachedPuzzle, error) {
+	entry, err := s.getEntry("request_hash", requestHash)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter registro do cache para o hash %s: %w", requestHash, err)
+	}
+	return entry, nil
+}
+
+// GetCachedPuzzleByID recupera o registro completo a partir do ID público do quebra-cabeça.
+// Retorna nil se nenhum registro tiver esse ID.
+func (s *DBService) GetCachedPuzzleByID(puzzleID string) (*CachedPuzzle, error) {
+	entry, err := s.getEntry("puzzle_id", puzzleID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter o quebra-cabeça %s: %w", puzzleID, err)
+	}
+	return entry, nil
+}
+
+// getEntry busca um registro completo pela coluna informada (request_hash ou puzzle_id).
+func (s *DBService) getEntry(column, value string) (*CachedPuzzle, error) {
+	var entry CachedPuzzle
+	var puzzleDate, promptVersion, model, schemaVersion sql.NullString
+	var requestParams, responseData []byte // json.RawMessage não é aceito diretamente por Scan
+	query := "SELECT request_hash, CAST(puzzle_date AS TEXT), prompt_version, model, schema_version, request_params, response_data, created_at FROM cached_puzzles WHERE " + column + " = $1"
+	err := s.db.QueryRow(query, value).Scan(&entry.RequestHash, &puzzleDate, &promptVersion, &model, &schemaVersion, &requestParams, &responseData, &entry.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry.PuzzleID = puzzleIDForHash(entry.RequestHash)
+	entry.PuzzleDate = puzzleDate.String
+	entry.PromptVersion = promptVersion.String
+	entry.Model = model.String
+	entry.SchemaVersion = schemaVersion.String
+	entry.RequestParams = requestParams
+	entry.ResponseData = responseData
+	return &entry, nil
+}
+
+// ListCachedPuzzles lista os registros do cache que atendem ao filtro, do mais recente para o mais antigo.
+func (s *DBService) ListCachedPuzzles(filter CacheFilter) ([]CachedPuzzle, error) {
+	where, args := s.filterClause(filter)
+	args = append(args, filter.limit(), filter.Offset)
+	query := fmt.Sprintf(
+		"SELECT request_hash, CAST(puzzle_date AS TEXT), prompt_version, model, schema_version, request_params, created_at FROM cached_puzzles%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d",
+		where, len(args)-1, len(args),
+	)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar o cache: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []CachedPuzzle{}
+	for rows.Next() {
+		var entry CachedPuzzle
+		var puzzleDate, promptVersion, model, schemaVersion sql.NullString
+		var requestParams []byte
+		if err := rows.Scan(&entry.RequestHash, &puzzleDate, &promptVersion, &model, &schemaVersion, &requestParams, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler registro do cache: %w", err)
+		}
+		entry.PuzzleID = puzzleIDForHash(entry.RequestHash)
+		entry.PuzzleDate = puzzleDate.String
+		entry.PromptVersion = promptVersion.String
+		entry.Model = model.String
+		entry.SchemaVersion = schemaVersion.String
+		entry.RequestParams = requestParams
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao listar o cache: %w", err)
+	}
+	return entries, nil
+}
+
+// DeleteCachedPuzzle remove o registro de um hash e informa se ele existia.
+func (s *DBService) DeleteCachedPuzzle(requestHash string) (bool, error) {
+	res, err := s.db.Exec("DELETE FROM cached_puzzles WHERE request_hash = $1", requestHash)
+	if err != nil {
+		return false, fmt.Errorf("falha ao remover o hash %s do cache: %w", requestHash, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("falha ao remover o hash %s do cache: %w", requestHash, err)
+	}
+	log.Printf("Cache removido para o hash: %s", requestHash)
+	return n > 0, nil
+}
+
+// PurgeCachedPuzzles remove todos os registros que atendem ao filtro e retorna quantos foram removidos.
+func (s *DBService) PurgeCachedPuzzles(filter CacheFilter) (int64, error) {
+	where, args := s.filterClause(filter)
+	res, err := s.db.Exec("DELETE FROM cached_puzzles"+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao expurgar o cache: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("falha ao expurgar o cache: %w", err)
+	}
+	log.Printf("Expurgados %d registros do cache.", n)
+	return n, nil
+}
+
+// filterClause monta a cláusula WHERE (com espaço inicial) e os argumentos para um CacheFilter.
+// Os campos da requisição são lidos de request_params com as funções JSON de cada dialeto.
+func (s *DBService) filterClause(filter CacheFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.GameType != "" {
+		add("lower("+s.jsonParam("gameType")+") = lower($%d)", filter.GameType)
+	}
+	if filter.Difficulty != "" {
+		add("lower("+s.jsonParam("difficulty")+") = lower($%d)", filter.Difficulty)
+	}
+	if filter.Language != "" {
+		add("lower("+s.jsonParam("language")+") = lower($%d)", filter.Language)
+	}
+	if filter.Topic != "" {
+		add("EXISTS (SELECT 1 FROM "+s.jsonTopics()+" WHERE lower(value) = lower($%d))", filter.Topic)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("created_at >= $%d", filter.CreatedFrom.UTC())
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("created_at < $%d", filter.CreatedTo.UTC())
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// jsonParam retorna a expressão SQL que extrai um campo texto de request_params.
+func (s *DBService) jsonParam(field string) string {
+	if s.dialect == CacheBackendSQLite {
+		return fmt.Sprintf("json_extract(request_params, '$.%s')", field)
+	}
+	return fmt.Sprintf("(request_params->>'%s')", field)
+}
+
+// jsonTopics retorna uma fonte de linhas com os tópicos de request_params na coluna "value".
+func (s *DBService) jsonTopics() string {
+	if s.dialect == CacheBackendSQLite {
+		return "json_each(request_params, '$.topics')"
+	}
+	return "jsonb_array_elements_text(request_params->'topics') AS t(value)"
+}
+
+// GetIdempotentResponse busca a resposta guardada para a Idempotency-Key do endpoint.
+// Retorna nil se a chave não tiver sido usada.
+func (s *DBService) GetIdempotentResponse(operation, key string) (*IdempotentResponse, error) {
+	resp := IdempotentResponse{Operation: operation, Key: key}
+	var headers []byte
+	query := "SELECT request_hash, status_code, headers, body, created_at FROM idempotency_keys WHERE operation = $1 AND idempotency_key = $2"
+	err := s.db.QueryRow(query, operation, key).Scan(&resp.RequestHash, &resp.StatusCode, &headers, &resp.Body, &resp.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter a Idempotency-Key %q de %s: %w", key, operation, err)
+	}
+	if err := json.Unmarshal(headers, &resp.Header); err != nil {
+		return nil, fmt.Errorf("cabeçalhos inválidos na Idempotency-Key %q de %s: %w", key, operation, err)
+	}
+	return &resp, nil
+}
+
+// SaveIdempotentResponse guarda a resposta da Idempotency-Key, substituindo um registro expirado com a mesma chave.
+func (s *DBService) SaveIdempotentResponse(resp *IdempotentResponse) error {
+	headers, err := json.Marshal(resp.Header)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO idempotency_keys (operation, idempotency_key, request_hash, status_code, headers, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (operation, idempotency_key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = EXCLUDED.status_code,
+			headers = EXCLUDED.headers,
+			body = EXCLUDED.body,
+			created_at = EXCLUDED.created_at
+	`
+	_, err = s.db.Exec(query, resp.Operation, resp.Key, resp.RequestHash, resp.StatusCode, string(headers), resp.Body, createdAtOrNow(resp.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("falha ao salvar a Idempotency-Key %q de %s: %w", resp.Key, resp.Operation, err)
+	}
+	return nil
+}
+
+// PurgeIdempotentResponses remove as respostas guardadas antes de before.
+func (s *DBService) PurgeIdempotentResponses(before time.Time) (int64, error) {
+	result, err := s.db.Exec("DELETE FROM idempotency_keys WHERE created_at < $1", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("falha ao expurgar as Idempotency-Keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// SaveWebhookDelivery registra uma tentativa de entrega de webhook.
+func (s *DBService) SaveWebhookDelivery(delivery *WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (delivery_id, job_id, url, event, attempt, status_code, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	statusCode := sql.NullInt64{Int64: int64(delivery.StatusCode), Valid: delivery.StatusCode != 0}
+	deliveryErr := sql.NullString{String: delivery.Error, Valid: delivery.Error != ""}
+	_, err := s.db.Exec(query, delivery.DeliveryID, delivery.JobID, delivery.URL, delivery.Event, delivery.Attempt,
+		statusCode, deliveryErr, delivery.DurationMS, createdAtOrNow(delivery.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("falha ao registrar a entrega %s do webhook: %w", delivery.DeliveryID, err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries lista as tentativas de entrega de webhooks, da mais recente para a mais antiga.
+func (s *DBService) ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	var where string
+	var args []interface{}
+	if filter.JobID != "" {
+		where, args = " WHERE job_id = $1", append(args, filter.JobID)
+	}
+	args = append(args, filter.limit(), filter.Offset)
+	query := fmt.Sprintf(
+		"SELECT delivery_id, job_id, url, event, attempt, status_code, error, duration_ms, created_at FROM webhook_deliveries%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d",
+		where, len(args)-1, len(args),
+	)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar as entregas de webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []WebhookDelivery{}
+	for rows.Next() {
+		var d WebhookDelivery
+		var statusCode sql.NullInt64
+		var deliveryErr sql.NullString
+		if err := rows.Scan(&d.DeliveryID, &d.JobID, &d.URL, &d.Event, &d.Attempt, &statusCode, &deliveryErr, &d.DurationMS, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler entrega de webhook: %w", err)
+		}
+		d.StatusCode = int(statusCode.Int64)
+		d.Error = deliveryErr.String
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao listar as entregas de webhooks: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GameTypeSpec descreve um tipo de jogo. Cada tipo se registra com RegisterGameType em uma função init
+// do seu próprio arquivo, informando o template do prompt, o modelo dos dados (T) e como verificar e
+// complementar a resposta. O schema enviado ao Gemini é gerado a partir de T (ver geminiSchemaFor). Os campos de função são opcionais; é preciso informar Prompt
+// (jogos gerados pelo Gemini) ou Generate (jogos gerados localmente).
+//
+// Erros de Validate são sempre corrigíveis pelo prompt de reparo. Os de PostProcess só são corrigíveis
+// quando são um *PuzzleValidationError (ver invalidPuzzle); os demais encerram a geração, pois nenhuma
+// resposta do Gemini os resolveria.
+type GameTypeSpec[T any] struct {
+	Name         string                                                   // Valor de PuzzleRequest.GameType (ex: "crossword")
+	Description  string                                                   // Nome do jogo usado no prompt (ex: "crossword puzzle")
+	DataField    string                                                   // Campo JSON da resposta com os dados do jogo (ex: "crosswordData")
+	Prompt       string                                                   // Nome do template do prompt (prompts/<Prompt>.tmpl)
+	CheckRequest func(req PuzzleRequest) error                            // Recusa, antes de gerar, requisições que o tipo não atende (ex: idioma sem lista de palavras)
+	Validate     func(data *T, req PuzzleRequest) error                   // Verificações do servidor sobre os dados gerados
+	PostProcess  func(data *T, req PuzzleRequest) error                   // Complementos calculados pelo proxy (ex: embaralhar letras)
+	Generate     func(ctx context.Context, req PuzzleRequest) (*T, error) // Geração local, sem o Gemini (ex: sudoku); deve parar quando ctx for cancelado
+}
+
+// promptParams são os dados disponíveis nos templates de prompt, já formatados para o texto.
+type promptParams struct {
+	GameType    string // Nome do tipo de jogo (ex: "crossword")
+	Difficulty  string // Dificuldade em minúsculas
+	Description string // Nome do jogo (GameTypeSpec.Description)
+	Language    string // Idioma pedido
+	Topics      string // "about x, y" ou "general knowledge"
+
+	Errors []string // Problemas da resposta anterior (apenas no template de reparo)
+}
+
+// PuzzleValidationError indica que o JSON gerado não atende ao schema ou às regras do tipo de jogo.
+// Problems lista cada problema encontrado; a lista é repassada ao Gemini no prompt de reparo.
+type PuzzleValidationError struct {
+	Problems []string
+}
+
+// Error junta os problemas em uma única mensagem.
+func (e *PuzzleValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// invalidPuzzle converte um erro de verificação em um PuzzleValidationError.
+func invalidPuzzle(err error) *PuzzleValidationError {
+	return &PuzzleValidationError{Problems: []string{err.Error()}}
+}
+
+// registeredGameType é a forma de um GameTypeSpec guardada no registro, sem o parâmetro de tipo.
+type registeredGameType struct {
+	name          string
+	description   string
+	dataField     string
+	dataType      reflect.Type // Modelo dos dados do jogo (T)
+	newData       func() interface{}
+	prompt        string        // Nome do template do prompt; vazio nos jogos gerados localmente
+	schema        *GeminiSchema // Schema completo da resposta, incluindo gameType, difficulty e topics
+	schemaVersion string        // Versão do formato dos dados (ver schemaVersionFor)
+	checkRequest  func(req PuzzleRequest) error
+	validate      func(data interface{}, req PuzzleRequest) error
+	postProcess   func(data interface{}, req PuzzleRequest) error
+	generate      func(ctx context.Context, req PuzzleRequest) (interface{}, error)
+}
+
+// gameTypeRegistry contém os tipos de jogo registrados, indexados pelo nome. É preenchido apenas
+// durante a inicialização do pacote e somente lido depois, então não precisa de mutex.
+var gameTypeRegistry = map[string]*registeredGameType{}
+
+// RegisterGameType adiciona um tipo de jogo ao registro. Deve ser chamada em uma função init;
+// entra em pânico se o tipo for inválido ou já estiver registrado.
+func RegisterGameType[T any](spec GameTypeSpec[T]) {
+	if spec.Name == "" || spec.DataField == "" {
+		panic("RegisterGameType: Name e DataField são obrigatórios")
+	}
+	if _, ok := gameTypeRegistry[spec.Name]; ok {
+		panic(fmt.Sprintf("RegisterGameType: tipo de jogo %q registrado duas vezes", spec.Name))
+	}
+	if (spec.Prompt == "") == (spec.Generate == nil) {
+		panic(fmt.Sprintf("RegisterGameType: o tipo %q precisa de exatamente um entre Prompt e Generate", spec.Name))
+	}
+
+	dataType := reflect.TypeOf((*T)(nil)).Elem()
+	gt := &registeredGameType{
+		dataType:     dataType,
+		name:         spec.Name,
+		description:  spec.Description,
+		dataField:    spec.DataField,
+		newData:      func() interface{} { return new(T) },
+		prompt:       spec.Prompt,
+		checkRequest: spec.CheckRequest,
+	}
+	schema, err := gameTypeResponseSchema(spec.Name, spec.DataField, dataType)
+	if err != nil {
+		panic(fmt.Sprintf("RegisterGameType: schema inválido para %q: %v", spec.Name, err))
+	}
+	gt.schemaVersion = schemaVersionFor(schema)
+	if spec.Prompt != "" {
+		gt.schema = schema
+	}
+	if spec.Validate != nil {
+		gt.validate = func(data interface{}, req PuzzleRequest) error { return spec.Validate(data.(*T), req) }
+	}
+	if spec.PostProcess != nil {
+		gt.postProcess = func(data interface{}, req PuzzleRequest) error { return spec.PostProcess(data.(*T), req) }
+	}
+	if spec.Generate != nil {
+		gt.generate = func(ctx context.Context, req PuzzleRequest) (interface{}, error) { return spec.Generate(ctx, req) }
+	}
+	gameTypeRegistry[spec.Name] = gt
+}
+
+// lookupGameType retorna o tipo de jogo registrado com o nome informado.
+func lookupGameType(name string) (*registeredGameType, bool) {
+	gt, ok := gameTypeRegistry[name]
+	return gt, ok
+}
+
+// gameTypeFor retorna o tipo de jogo da requisição, antes de qualquer chamada ao Gemini. Recusa gameTypes
+// fora do registro (*UnsupportedGameTypeError) e requisições que o tipo não atende (*UnsupportedRequestError).
+func gameTypeFor(req PuzzleRequest) (*registeredGameType, error) {
+	gt, ok := lookupGameType(req.GameType)
+	if !ok {
+		return nil, &UnsupportedGameTypeError{GameType: req.GameType}
+	}
+	if gt.checkRequest != nil {
+		if err := gt.checkRequest(req); err != nil {
+			return nil, &UnsupportedRequestError{GameType: gt.name, Err: err}
+		}
+	}
+	return gt, nil
+}
+
+// gameTypeNames retorna os nomes dos tipos de jogo registrados, em ordem alfabética.
+func gameTypeNames() []string {
+	names := make([]string, 0, len(gameTypeRegistry))
+	for name := range gameTypeRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// geminiGameTypeNames retorna os nomes dos tipos de jogo gerados pelo Gemini (sem os locais), em ordem alfabética.
+func geminiGameTypeNames() []string {
+	var names []string
+	for _, name := range gameTypeNames() {
+		if !gameTypeRegistry[name].isLocal() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// gameTypeResponseSchema monta o schema completo da resposta a partir de GeminiPuzzleResponse (gameType,
+// difficulty e topics, com gameType restrito ao tipo de jogo) e do modelo dos dados do jogo.
+func gameTypeResponseSchema(name, dataField string, dataType reflect.Type) (*GeminiSchema, error) {
+	schema, err := geminiSchemaFor(reflect.TypeOf(geminiPuzzleResponseFields{}))
+	if err != nil {
+		return nil, err
+	}
+	data, err := geminiSchemaFor(dataType)
+	if err != nil {
+		return nil, err
+	}
+	schema.Properties["gameType"].Enum = []string{name}
+	schema.Properties[dataField] = data
+	schema.Required = append(schema.Required, dataField)
+	return schema, nil
+}
+
+// schemaVersionFor deriva a versão do formato dos dados de um tipo de jogo: os primeiros 12 caracteres
+// do SHA256 do schema em JSON. Qualquer mudança no modelo Go (campos, tipos, enums ou descrições) muda a
+// versão, o que permite aos clientes detectar quebra-cabeças gerados com um formato antigo.
+func schemaVersionFor(schema *GeminiSchema) string {
+	b, _ := json.Marshal(schema) // Mapas são serializados com as chaves ordenadas, então o resultado é estável.
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// promptParamsFor formata os parâmetros da requisição para os prompts do tipo de jogo.
+func (gt *registeredGameType) promptParamsFor(req PuzzleRequest) promptParams {
+	topics := "general knowledge" // Tópico padrão se nenhum for fornecido.
+	if len(req.Topics) > 0 {
+		topics = fmt.Sprintf("about %s", strings.Join(req.Topics, ", "))
+	}
+	return promptParams{
+		GameType:    gt.name,
+		Difficulty:  strings.ToLower(req.Difficulty),
+		Description: gt.description,
+		Language:    req.Language,
+		Topics:      topics,
+	}
+}
+
+// isLocal informa se o tipo de jogo é gerado pelo próprio proxy, sem chamar o Gemini.
+func (gt *registeredGameType) isLocal() bool {
+	return gt.generate != nil
+}
+
+// generateLocal gera o quebra-cabeça localmente e o retorna no mesmo formato JSON das respostas do Gemini.
+func (gt *registeredGameType) generateLocal(ctx context.Context, req PuzzleRequest) ([]byte, error) {
+	data, err := gt.generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(GeminiPuzzleResponse{
+		GameType:   gt.name,
+		Difficulty: strings.ToLower(req.Difficulty),
+		Topics:     req.Topics,
+		Data:       data,
+	})
+}
+
+// processResponse decodifica o JSON gerado pelo Gemini, valida-o contra o schema e as regras do tipo de jogo,
+// aplica os complementos e retorna o JSON final, que é o que vai para o cache. As falhas que o Gemini pode
+// corrigir são retornadas como *PuzzleValidationError; as demais falhas de PostProcess, como vieram.
+func (gt *registeredGameType) processResponse(data []byte, req PuzzleRequest) ([]byte, error) {
+	// Primeiro valida o JSON genérico contra o schema, o que aponta todos os campos ausentes ou de tipo errado.
+	var raw interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, invalidPuzzle(fmt.Errorf("resposta não é um JSON válido: %w", err))
+	}
+	if problems := gt.schema.Validate(raw); len(problems) > 0 {
+		return nil, &PuzzleValidationError{Problems: problems}
+	}
+
+	var resp GeminiPuzzleResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, invalidPuzzle(fmt.Errorf("resposta não corresponde ao modelo: %w", err))
+	}
+	if resp.Data == nil {
+		return nil, invalidPuzzle(fmt.Errorf("%s ausente", gt.dataField))
+	}
+	if gt.validate != nil {
+		if err := gt.validate(resp.Data, req); err != nil {
+			return nil, invalidPuzzle(err)
+		}
+	}
+	if gt.postProcess != nil {
+		if err := gt.postProcess(resp.Data, req); err != nil {
+			var invalid *PuzzleValidationError
+			if errors.As(err, &invalid) {
+				return nil, invalid
+			}
+			return nil, fmt.Errorf("falha ao completar %s: %w", gt.name, err)
+		}
+	}
+	return json.Marshal(resp)
+}
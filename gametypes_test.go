@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGameTypeFor(t *testing.T) {
+	tests := []struct {
+		req        PuzzleRequest
+		wantStatus int // 0 quando a requisição é aceita
+	}{
+		{PuzzleRequest{GameType: "crossword", Language: "xx"}, 0},
+		{PuzzleRequest{GameType: "wordladder", Language: "en"}, 0},
+		{PuzzleRequest{GameType: "wordladder", Language: "pt-BR"}, 0},
+		{PuzzleRequest{GameType: "wordladder", Language: "Portuguese"}, 0},
+		{PuzzleRequest{GameType: "wordladder", Language: "klingon"}, http.StatusBadRequest},
+		{PuzzleRequest{GameType: "chess", Language: "en"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		_, err := gameTypeFor(tt.req)
+		if tt.wantStatus == 0 {
+			if err != nil {
+				t.Errorf("gameTypeFor(%+v) = %v, quer nil", tt.req, err)
+			}
+			continue
+		}
+		if status, _ := generationErrorStatus(err); status != tt.wantStatus {
+			t.Errorf("gameTypeFor(%+v) = %v (status %d), quer status %d", tt.req, err, status, tt.wantStatus)
+		}
+	}
+}
+
+// O Server de teste não tem GeminiPuzzleService: uma requisição recusada antes da geração não pode
+// chegar ao Gemini.
+func TestGenerateRejectsUnsupportedLanguageBeforeGemini(t *testing.T) {
+	s := &Server{store: NewMemoryStore()}
+	_, err := s.generate(context.Background(), PuzzleRequest{GameType: "wordladder", Difficulty: "easy", Language: "klingon"})
+	var unsupported *UnsupportedRequestError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("generate = %v, quer *UnsupportedRequestError", err)
+	}
+}
+
+func TestProcessResponsePostProcessErrors(t *testing.T) {
+	gt, _ := lookupGameType("wordladder")
+	response := []byte(`{"gameType":"wordladder","difficulty":"easy","topics":["animals"],"wordLadderData":{"candidateWords":["QXZQX","ZQXZQ"]}}`)
+
+	// Candidatas fora do dicionário: o Gemini pode sugerir outras, então o erro é corrigível.
+	_, err := gt.processResponse(response, PuzzleRequest{GameType: "wordladder", Difficulty: "easy", Language: "en"})
+	var invalid *PuzzleValidationError
+	if !errors.As(err, &invalid) {
+		t.Errorf("candidatas inválidas: erro = %v, quer *PuzzleValidationError", err)
+	}
+
+	// Sem lista de palavras, nenhuma resposta resolve: o erro não pode levar a um prompt de reparo.
+	_, err = gt.processResponse(response, PuzzleRequest{GameType: "wordladder", Difficulty: "easy", Language: "klingon"})
+	if err == nil || errors.As(err, &invalid) {
+		t.Errorf("idioma sem lista: erro = %v, quer um erro que não seja *PuzzleValidationError", err)
+	}
+}
+
+// Uma resposta que passa pelo schema mas quebra uma regra do tipo de jogo deve virar um
+// PuzzleValidationError, cujos problemas são listados no prompt de reparo.
+func TestProcessResponseRuleFailureBecomesRepairPrompt(t *testing.T) {
+	prompts, err := LoadPrompts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		gameType string
+		data     string
+		problem  string // Trecho esperado no problema; vazio quando a resposta é válida
+	}{
+		{
+			name:     "palavras cruzadas válidas",
+			gameType: "crossword",
+			data:     `"crosswordData":{"gridSize":{"rows":5,"cols":5},"words":[{"word":"gato","clue":"Felino","startRow":0,"startCol":0,"direction":"across"},{"word":"GALO","clue":"Ave","startRow":0,"startCol":0,"direction":"down"}]}`,
+		},
+		{
+			name:     "palavra cruzada fora da grade",
+			gameType: "crossword",
+			data:     `"crosswordData":{"gridSize":{"rows":5,"cols":5},"words":[{"word":"CAVALO","clue":"Equino","startRow":1,"startCol":0,"direction":"across"}]}`,
+			problem:  `palavra "CAVALO" (across, início em linha 1, coluna 0) não cabe na grade 5x5`,
+		},
+		{
+			name:     "palavra cruzada começando fora da grade",
+			gameType: "crossword",
+			data:     `"crosswordData":{"gridSize":{"rows":5,"cols":5},"words":[{"word":"GATO","clue":"Felino","startRow":-1,"startCol":0,"direction":"down"}]}`,
+			problem:  "não cabe na grade",
+		},
+		{
+			name:     "cruzamento com letras diferentes",
+			gameType: "crossword",
+			data:     `"crosswordData":{"gridSize":{"rows":5,"cols":5},"words":[{"word":"GATO","clue":"Felino","startRow":0,"startCol":0,"direction":"across"},{"word":"PATO","clue":"Ave","startRow":0,"startCol":0,"direction":"down"}]}`,
+			problem:  `palavras "GATO" e "PATO" se cruzam na linha 0, coluna 0 com letras diferentes (G e P)`,
+		},
+		{
+			name:     "caça-palavras válido",
+			gameType: "wordsearch",
+			data:     `"wordSearchData":{"gridSize":{"rows":5,"cols":8},"wordsToFind":["elefante","GATO"]}`,
+		},
+		{
+			name:     "palavra maior que a grade",
+			gameType: "wordsearch",
+			data:     `"wordSearchData":{"gridSize":{"rows":5,"cols":5},"wordsToFind":["GATO","ELEFANTE"]}`,
+			problem:  `palavra "ELEFANTE" tem 8 letras e não cabe na grade 5x5`,
+		},
+		{
+			name:     "palavra com espaço",
+			gameType: "wordsearch",
+			data:     `"wordSearchData":{"gridSize":{"rows":10,"cols":10},"wordsToFind":["BEIJA FLOR"]}`,
+			problem:  "que não é uma letra",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gt, _ := lookupGameType(tt.gameType)
+			req := PuzzleRequest{GameType: tt.gameType, Difficulty: "easy", Topics: []string{"animais"}, Language: "pt"}
+			response := `{"gameType":"` + tt.gameType + `","difficulty":"easy","topics":["animais"],` + tt.data + `}`
+
+			_, err := gt.processResponse([]byte(response), req)
+			if tt.problem == "" {
+				if err != nil {
+					t.Fatalf("processResponse = %v, quer nil", err)
+				}
+				return
+			}
+			var invalid *PuzzleValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("processResponse = %v, quer *PuzzleValidationError", err)
+			}
+
+			params := gt.promptParamsFor(req)
+			params.Errors = invalid.Problems
+			repair, err := prompts.Render(repairPromptName, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(repair, "- ") || !strings.Contains(repair, tt.problem) {
+				t.Errorf("prompt de reparo não lista o problema %q:\n%s", tt.problem, repair)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Métricas publicadas em expvar (GET /admin/metrics), contadas por motivo.
+var (
+	geminiFinishReasons  = expvar.NewMap("gemini_finish_reasons")  // finishReason de cada candidato recebido
+	geminiBlockReasons   = expvar.NewMap("gemini_block_reasons")   // promptFeedback.blockReason dos prompts recusados
+	geminiErrors         = expvar.NewMap("gemini_errors")          // Falhas de geração por tipo de erro
+	geminiModelFallbacks = expvar.NewMap("gemini_model_fallbacks") // Falhas de cada modelo que levaram ao próximo da cadeia
+)
+
+// GeminiBlockedError indica que o Gemini recusou o pedido por políticas de conteúdo, seja o prompt
+// (promptFeedback.blockReason) ou a resposta (finishReason SAFETY, RECITATION, BLOCKLIST etc.).
+// Normalmente é causado pelos tópicos pedidos, então tentar de novo não adianta.
+type GeminiBlockedError struct {
+	Reason     string   // blockReason ou finishReason informado pelo Gemini
+	Prompt     bool     // true se o prompt foi bloqueado antes da geração
+	Categories []string // Categorias de segurança que causaram o bloqueio, se informadas
+}
+
+func (e *GeminiBlockedError) Error() string {
+	what := "a resposta"
+	if e.Prompt {
+		what = "o prompt"
+	}
+	msg := fmt.Sprintf("o Gemini bloqueou %s (%s)", what, e.Reason)
+	if len(e.Categories) > 0 {
+		msg += ": " + strings.Join(e.Categories, ", ")
+	}
+	return msg
+}
+
+// GeminiTruncatedError indica que a geração parou no limite de tokens (finishReason MAX_TOKENS),
+// então o JSON recebido está incompleto.
+type GeminiTruncatedError struct{}
+
+func (e *GeminiTruncatedError) Error() string {
+	return "resposta do Gemini truncada pelo limite de tokens (MAX_TOKENS)"
+}
+
+// GeminiAPIError é uma resposta HTTP de erro da API Gemini (ex: 429 por limite de uso, 5xx por indisponibilidade).
+type GeminiAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *GeminiAPIError) Error() string {
+	return fmt.Sprintf("API Gemini falhou com status %d: %s", e.StatusCode, e.Body)
+}
+
+// GeminiEmptyResponseError indica uma resposta sem conteúdo que não se encaixa nos outros casos
+// (ex: finishReason OTHER ou nenhum candidato).
+type GeminiEmptyResponseError struct {
+	FinishReason string
+	Body         string
+}
+
+func (e *GeminiEmptyResponseError) Error() string {
+	return fmt.Sprintf("A resposta da API Gemini estava vazia ou inesperada (finishReason %q). Resposta bruta: %s", e.FinishReason, e.Body)
+}
+
+// geminiBlockedFinishReasons são os finishReason que indicam bloqueio por políticas de conteúdo.
+var geminiBlockedFinishReasons = map[string]bool{
+	"SAFETY":             true,
+	"RECITATION":         true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+	"IMAGE_SAFETY":       true,
+}
+
+// checkGeminiResponse interpreta promptFeedback e finishReason, registra as métricas e retorna o texto
+// gerado ou o erro correspondente ao motivo pelo qual não há uma resposta completa.
+func checkGeminiResponse(resp *GeminiAPIResponse, body []byte) (string, error) {
+	if fb := resp.PromptFeedback; fb != nil && fb.BlockReason != "" {
+		geminiBlockReasons.Add(fb.BlockReason, 1)
+		return "", &GeminiBlockedError{Reason: fb.BlockReason, Prompt: true, Categories: blockedCategories(fb.SafetyRatings)}
+	}
+	if len(resp.Candidates) == 0 {
+		return "", &GeminiEmptyResponseError{Body: string(body)}
+	}
+
+	candidate := resp.Candidates[0]
+	reason := candidate.FinishReason
+	if reason != "" {
+		geminiFinishReasons.Add(reason, 1)
+	}
+	switch {
+	case geminiBlockedFinishReasons[reason]:
+		return "", &GeminiBlockedError{Reason: reason, Categories: blockedCategories(candidate.SafetyRatings)}
+	case reason == "MAX_TOKENS":
+		return "", &GeminiTruncatedError{}
+	case reason != "" && reason != "STOP":
+		return "", &GeminiEmptyResponseError{FinishReason: reason, Body: string(body)}
+	case len(candidate.Content.Parts) == 0:
+		return "", &GeminiEmptyResponseError{FinishReason: reason, Body: string(body)}
+	}
+	return candidate.Content.Parts[0].Text, nil
+}
+
+// blockedCategories retorna as categorias marcadas como bloqueadas nas avaliações de segurança.
+func blockedCategories(ratings []GeminiSafetyRating) []string {
+	var categories []string
+	for _, r := range ratings {
+		if r.Blocked {
+			categories = append(categories, r.Category)
+		}
+	}
+	return categories
+}
+
+// generationErrorStatus escolhe o status HTTP e a mensagem para o cliente de uma falha de geração,
+// e conta a falha em gemini_errors.
+//
+//	UnsupportedGameTypeError 400  gameType fora do registro
+//	UnsupportedRequestError  400  requisição que o tipo de jogo não atende (ex: idioma sem lista de palavras)
+//	GeminiBlockedError       422  o tema ou o conteúdo pedido foi recusado pelo Gemini
+//	GeminiAPIError 429       503  limite de uso da API Gemini atingido
+//	errAllKeysThrottled      503  todas as chaves em espera após 429
+//	GeminiAPIError, demais   502
+//	GeminiTruncatedError     502
+//	GeminiEmptyResponseError 502
+//	PuzzleValidationError    502  o Gemini não gerou um quebra-cabeça válido
+//	outros                   500
+func generationErrorStatus(err error) (int, string) {
+	var (
+		blocked   *GeminiBlockedError
+		apiErr    *GeminiAPIError
+		truncated *GeminiTruncatedError
+		empty     *GeminiEmptyResponseError
+		invalid   *PuzzleValidationError
+		gameType  *UnsupportedGameTypeError
+		request   *UnsupportedRequestError
+		queueFull *JobQueueFullError
+	)
+	switch {
+	case errors.As(err, &gameType):
+		return http.StatusBadRequest, gameType.Error()
+	case errors.As(err, &request):
+		return http.StatusBadRequest, request.Error()
+	case errors.As(err, &queueFull):
+		return http.StatusTooManyRequests, "Muitos jobs na fila. Tente novamente mais tarde."
+	case errors.Is(err, errAllKeysThrottled):
+		geminiErrors.Add("keys_throttled", 1)
+		return http.StatusServiceUnavailable, "Limite de uso da API Gemini atingido. Tente novamente mais tarde."
+	case errors.As(err, &blocked):
+		geminiErrors.Add("blocked", 1)
+		return http.StatusUnprocessableEntity, fmt.Sprintf("O conteúdo pedido foi bloqueado pelo Gemini (%s). Tente outros tópicos.", blocked.Reason)
+	case errors.As(err, &apiErr):
+		geminiErrors.Add(fmt.Sprintf("api_%d", apiErr.StatusCode), 1)
+		if apiErr.StatusCode == http.StatusTooManyRequests {
+			return http.StatusServiceUnavailable, "Limite de uso da API Gemini atingido. Tente novamente mais tarde."
+		}
+		return http.StatusBadGateway, fmt.Sprintf("A API Gemini falhou com status %d.", apiErr.StatusCode)
+	case errors.As(err, &truncated):
+		geminiErrors.Add("truncated", 1)
+		return http.StatusBadGateway, "A resposta do Gemini foi truncada."
+	case errors.As(err, &empty):
+		geminiErrors.Add("empty", 1)
+		return http.StatusBadGateway, "A resposta do Gemini veio vazia."
+	case errors.As(err, &invalid):
+		geminiErrors.Add("invalid", 1)
+		return http.StatusBadGateway, fmt.Sprintf("O Gemini não gerou um quebra-cabeça válido: %v", invalid)
+	default:
+		return http.StatusInternalServerError, fmt.Sprintf("Falha ao gerar quebra-cabeça: %v", err)
+	}
+}
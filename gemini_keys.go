@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Estratégias de escolha da chave da API Gemini (GEMINI_KEY_SELECTION).
+const (
+	KeySelectionRoundRobin     = "round-robin"     // Usa as chaves em rodízio.
+	KeySelectionLeastThrottled = "least-throttled" // Prefere a chave que recebeu 429 há mais tempo (ou nunca).
+)
+
+// errAllKeysThrottled indica que todas as chaves estão em espera após respostas 429.
+var errAllKeysThrottled = errors.New("todas as chaves da API Gemini estão em espera por limite de uso")
+
+// apiKey é uma chave do pool com o estado usado na escolha.
+type apiKey struct {
+	value          string
+	index          int       // Posição na configuração, usada nos logs em vez da chave
+	lastUsed       time.Time // Último uso, para desempatar a estratégia least-throttled
+	lastThrottled  time.Time // Último 429 recebido
+	throttledUntil time.Time // Fim da espera; a chave não é usada antes disso
+}
+
+// apiKeyPool distribui as requisições entre as chaves da API Gemini e tira de uso por um tempo
+// as chaves que recebem 429. É seguro para uso concorrente.
+type apiKeyPool struct {
+	mu        sync.Mutex
+	keys      []*apiKey
+	selection string
+	cooldown  time.Duration
+	next      int // Próxima posição do rodízio
+}
+
+// newAPIKeyPool cria o pool com as chaves e a estratégia da configuração.
+func newAPIKeyPool(cfg GeminiConfig) *apiKeyPool {
+	p := &apiKeyPool{selection: cfg.KeySelection, cooldown: cfg.KeyCooldown}
+	for i, key := range cfg.APIKeys {
+		p.keys = append(p.keys, &apiKey{value: key, index: i + 1})
+	}
+	return p
+}
+
+// size retorna o número de chaves do pool.
+func (p *apiKeyPool) size() int {
+	return len(p.keys)
+}
+
+// acquire escolhe a chave da próxima requisição entre as que não estão em espera.
+func (p *apiKeyPool) acquire() (*apiKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var chosen *apiKey
+	switch p.selection {
+	case KeySelectionLeastThrottled:
+		for _, k := range p.keys {
+			if now.Before(k.throttledUntil) {
+				continue
+			}
+			if chosen == nil || k.lastThrottled.Before(chosen.lastThrottled) ||
+				(k.lastThrottled.Equal(chosen.lastThrottled) && k.lastUsed.Before(chosen.lastUsed)) {
+				chosen = k
+			}
+		}
+	default:
+		for i := range p.keys {
+			k := p.keys[(p.next+i)%len(p.keys)]
+			if !now.Before(k.throttledUntil) {
+				chosen = k
+				p.next = (p.next + i + 1) % len(p.keys)
+				break
+			}
+		}
+	}
+	if chosen == nil {
+		return nil, errAllKeysThrottled
+	}
+	chosen.lastUsed = now
+	return chosen, nil
+}
+
+// throttle coloca a chave em espera após um 429. retryAfter é o tempo pedido pelo servidor no
+// cabeçalho Retry-After; se for zero, usa o cooldown configurado.
+func (p *apiKeyPool) throttle(k *apiKey, retryAfter time.Duration) time.Duration {
+	if retryAfter <= 0 {
+		retryAfter = p.cooldown
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k.lastThrottled = time.Now()
+	k.throttledUntil = k.lastThrottled.Add(retryAfter)
+	return retryAfter
+}
+
+// String identifica a chave nos logs sem expor seu valor.
+func (k *apiKey) String() string {
+	return fmt.Sprintf("chave %d", k.index)
+}
+
+// parseRetryAfter lê o cabeçalho Retry-After em segundos ou como data HTTP; retorna zero se ausente ou inválido.
+func parseRetryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
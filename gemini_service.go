@@ -2,197 +2,130 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"strings"
 )
 
-// geminiAPIURL é o endpoint para o modelo Gemini 2.0 Flash.
-const geminiAPIURL = "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent"
+// geminiAPIURL é o endpoint generateContent da API Gemini; %s é o nome do modelo (ex: "gemini-2.0-flash").
+const geminiAPIURL = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent"
 
 // GeminiPuzzleService lida com as interações com a API Gemini.
 type GeminiPuzzleService struct {
-	apiKey string // A chave da API Gemini, mantida secreta no servidor.
+	keys        *apiKeyPool  // As chaves da API Gemini, mantidas secretas no servidor.
+	client      *http.Client // Cliente HTTP compartilhado por todas as chamadas, com pool de conexões.
+	maxAttempts int          // Tentativas por modelo, incluindo as de reparo.
+	models      ModelChains  // Modelos por tipo de jogo e dificuldade, em ordem de preferência.
+	prompts     *PromptSet   // Templates de prompt dos tipos de jogo.
 }
 
 // NewGeminiPuzzleService cria e retorna uma nova instância de GeminiPuzzleService.
-// Requer que a chave da API Gemini seja passada durante a inicialização.
-func NewGeminiPuzzleService(apiKey string) *GeminiPuzzleService {
-	return &GeminiPuzzleService{apiKey: apiKey}
-}
-
-// GeneratePuzzle constrói o prompt e o schema apropriados, então chama a API Gemini
-// para gerar um quebra-cabeça com base nos parâmetros de requisição fornecidos.
-// Retorna a resposta JSON bruta do Gemini como um slice de bytes ou um erro.
-func (s *GeminiPuzzleService) GeneratePuzzle(req PuzzleRequest) ([]byte, error) {
-	// Validação básica para a chave da API.
-	if s.apiKey == "" || s.apiKey == "YOUR_GEMINI_API_KEY_HERE" {
-		return nil, fmt.Errorf("GEMINI_API_KEY não definida ou é o valor padrão. Por favor, defina-a como uma variável de ambiente")
+// Requer que a configuração do Gemini e os templates de prompt sejam passados durante a inicialização.
+func NewGeminiPuzzleService(cfg GeminiConfig, prompts *PromptSet) *GeminiPuzzleService {
+	return &GeminiPuzzleService{
+		keys:        newAPIKeyPool(cfg),
+		client:      newUpstreamClient(cfg.HTTP),
+		maxAttempts: max(cfg.MaxAttempts, 1),
+		models:      cfg.Models,
+		prompts:     prompts,
 	}
+}
 
-	// Determina a string do tipo de jogo para o prompt.
-	gameTypeString := ""
-	if req.GameType == "crossword" {
-		gameTypeString = "crossword puzzle"
-	} else { // Assumindo req.GameType == "wordsearch"
-		gameTypeString = "word search puzzle"
-	}
+// Models retorna a cadeia de modelos usada para a requisição; o primeiro é o modelo preferido.
+func (s *GeminiPuzzleService) Models(req PuzzleRequest) []string {
+	return s.models.For(req.GameType, req.Difficulty)
+}
 
-	// Normaliza a string de dificuldade para minúsculas.
-	difficultyString := strings.ToLower(req.Difficulty)
+// PromptVersion retorna a versão do template de prompt usado pelo tipo de jogo.
+func (s *GeminiPuzzleService) PromptVersion(gt *registeredGameType) string {
+	return s.prompts.Version(gt.prompt)
+}
 
-	// Constrói a string de tópicos para o prompt.
-	topicsString := ""
-	if len(req.Topics) > 0 {
-		topicsString = fmt.Sprintf("about %s", strings.Join(req.Topics, ", "))
-	} else {
-		topicsString = "general knowledge" // Tópico padrão se nenhum for fornecido.
+// GeneratePuzzle monta o prompt a partir do template e usa o schema registrado para o tipo de jogo, então chama
+// a API Gemini para gerar um quebra-cabeça com base nos parâmetros de requisição fornecidos.
+//
+// Os modelos configurados para o tipo de jogo e a dificuldade são tentados em ordem: se um modelo falhar ou não
+// gerar um quebra-cabeça válido, o próximo da cadeia é usado. Bloqueios de conteúdo não passam para o próximo
+// modelo, pois se devem ao pedido. Retorna o JSON do quebra-cabeça, já verificado e complementado, e o modelo
+// que o gerou; respostas inválidas nunca são retornadas. Cancelar ctx interrompe a chamada em andamento e
+// encerra a geração sem tentar os modelos seguintes.
+func (s *GeminiPuzzleService) GeneratePuzzle(ctx context.Context, gt *registeredGameType, req PuzzleRequest) ([]byte, string, error) {
+	// Validação básica para as chaves da API.
+	if s.keys.size() == 0 {
+		return nil, "", fmt.Errorf("GEMINI_API_KEY não definida. Por favor, defina-a como uma variável de ambiente")
 	}
 
-	prompt := ""
-	var schemaBytes []byte // Usaremos um slice de bytes temporário para o schema JSON
-
-	// Lógica para construir o prompt e o schema de resposta com base no tipo de jogo.
-	if req.GameType == "crossword" {
-		prompt = fmt.Sprintf(`
-			Generate a %s %s in %s.
-			%s
-			Provide a grid of 8x8 to 10x10.
-			Return the data as a JSON object with 'gameType' (crossword), 'difficulty', 'topics', and 'crosswordData'.
-			'crosswordData' should contain 'gridSize' (rows, cols) and an array of 'words'.
-			Each 'word' object should have 'word', 'clue', 'startRow', 'startCol' (0-indexed), and 'direction' ('across' or 'down').
-			Ensure words fit the grid and intersect correctly without gaps. All cells in a word must be valid letters.
-			Prioritize well-formed and solvable puzzles.
-		`, difficultyString, gameTypeString, req.Language, topicsString)
-
-		// Schema JSON específico para palavras cruzadas.
-		schemaBytes = []byte(`{
-			"type": "OBJECT",
-			"properties": {
-				"gameType": {
-					"type": "STRING",
-					"enum": ["crossword"]
-				},
-				"difficulty": {
-					"type": "STRING",
-					"enum": ["easy", "medium", "hard"]
-				},
-				"topics": {
-					"type": "ARRAY",
-					"items": {"type": "STRING"}
-				},
-				"crosswordData": {
-					"type": "OBJECT",
-					"properties": {
-						"gridSize": {
-							"type": "OBJECT",
-							"properties": {
-								"rows": {"type": "INTEGER"},
-								"cols": {"type": "INTEGER"}
-							},
-							"required": ["rows", "cols"]
-						},
-						"words": {
-							"type": "ARRAY",
-							"items": {
-								"type": "OBJECT",
-								"properties": {
-									"word": {"type": "STRING"},
-									"clue": {"type": "STRING"},
-									"startRow": {"type": "INTEGER"},
-									"startCol": {"type": "INTEGER"},
-									"direction": {
-										"type": "STRING",
-										"enum": ["across", "down"]
-									}
-								},
-								"required": ["word", "clue", "startRow", "startCol", "direction"]
-							}
-						}
-					},
-					"required": ["gridSize", "words"]
-				}
-			},
-			"required": ["gameType", "difficulty", "topics"]
-		}`)
-	} else { // Caça-palavras
-		prompt = fmt.Sprintf(`
-			Generate a %s %s in %s.
-			%s
-			Provide a grid size based on difficulty: Easy (10x10), Medium (12x12), Hard (15x15).
-			Return the data as a JSON object with 'gameType' (wordsearch), 'difficulty', 'topics', and 'wordSearchData'.
-			'wordSearchData' should contain 'gridSize' (rows, cols) and a list of 'wordsToFind'.
-			**Crucially, do NOT generate the full grid of letters. ONLY provide gridSize and wordsToFind.**
-			The 'wordsToFind' list should contain 10-15 unique words (depending on difficulty) that are relevant to the topics and suitable for a word search puzzle (e.g., no spaces, only letters, common vocabulary).
-			Ensure these words are always in the uppercase.
-			Prioritize well-formed words and a good mix for the chosen difficulty.
-		`, difficultyString, gameTypeString, req.Language, topicsString)
-
-		// Schema JSON específico para caça-palavras.
-		schemaBytes = []byte(`{
-			"type": "OBJECT",
-			"properties": {
-				"gameType": {
-					"type": "STRING",
-					"enum": ["wordsearch"]
-				},
-				"difficulty": {
-					"type": "STRING",
-					"enum": ["easy", "medium", "hard"]
-				},
-				"topics": {
-					"type": "ARRAY",
-					"items": {"type": "STRING"}
-				},
-				"wordSearchData": {
-					"type": "OBJECT",
-					"properties": {
-						"gridSize": {
-							"type": "OBJECT",
-							"properties": {
-								"rows": {"type": "INTEGER"},
-								"cols": {"type": "INTEGER"}
-							},
-							"required": ["rows", "cols"]
-						},
-						"wordsToFind": {
-							"type": "ARRAY",
-							"items": {"type": "STRING"}
-						}
-					},
-					"required": ["gridSize", "wordsToFind"]
-				}
-			},
-			"required": ["gameType", "difficulty", "topics"]
-		}`)
+	params := gt.promptParamsFor(req)
+	prompt, err := s.prompts.Render(gt.prompt, params)
+	if err != nil {
+		return nil, "", err
 	}
 
-	// NOVO: Parse o schema JSON em um map e então marshal de volta para RawMessage.
-	// Isso garante que o json.RawMessage contenha JSON válido.
-	var parsedSchema map[string]interface{}
-	if err := json.Unmarshal(schemaBytes, &parsedSchema); err != nil {
-		return nil, fmt.Errorf("falha ao parsear o schema JSON para map: %w", err)
+	models := s.Models(req)
+	for i, model := range models {
+		puzzle, err := s.generateWithModel(ctx, model, gt, req, params, prompt)
+		if err == nil {
+			return puzzle, model, nil
+		}
+		var blocked *GeminiBlockedError
+		if errors.As(err, &blocked) || ctx.Err() != nil || i == len(models)-1 {
+			return nil, "", err
+		}
+		geminiModelFallbacks.Add(model, 1)
+		log.Printf("Modelo %s falhou para %s; tentando %s: %v", model, gt.name, models[i+1], err)
 	}
-	responseSchema, err := json.Marshal(parsedSchema)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao serializar o map do schema para json.RawMessage: %w", err)
+	return nil, "", fmt.Errorf("nenhum modelo Gemini configurado para %s", gt.name)
+}
+
+// generateWithModel gera o quebra-cabeça com um modelo. A resposta é validada contra o schema e as regras do
+// tipo de jogo; se for inválida, a conversa continua com um prompt de reparo que lista os problemas
+// encontrados, até maxAttempts tentativas.
+func (s *GeminiPuzzleService) generateWithModel(ctx context.Context, model string, gt *registeredGameType, req PuzzleRequest, params promptParams, prompt string) ([]byte, error) {
+	contents := []GeminiContent{{Role: "user", Parts: []GeminiContentPart{{Text: prompt}}}}
+
+	var validationErr *PuzzleValidationError
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		text, err := s.generateContent(ctx, model, contents, gt.schema)
+		if err != nil {
+			return nil, err // Falhas e bloqueios da API não são corrigidos por um prompt de reparo.
+		}
+
+		// O tipo de jogo verifica e complementa a resposta antes que ela seja retornada (e, portanto, cacheada).
+		puzzle, err := gt.processResponse([]byte(text), req)
+		if err == nil {
+			return puzzle, nil
+		}
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		log.Printf("Tentativa %d/%d (%s): %s inválido gerado pela API Gemini: %v", attempt, s.maxAttempts, model, gt.name, validationErr)
+
+		// Continua a conversa com a resposta inválida e o pedido de correção.
+		params.Errors = validationErr.Problems
+		repair, err := s.prompts.Render(repairPromptName, params)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents,
+			GeminiContent{Role: "model", Parts: []GeminiContentPart{{Text: text}}},
+			GeminiContent{Role: "user", Parts: []GeminiContentPart{{Text: repair}}},
+		)
 	}
+	return nil, fmt.Errorf("%s inválido gerado pelo modelo %s após %d tentativas: %w", gt.name, model, s.maxAttempts, validationErr)
+}
 
+// generateContent envia a conversa ao modelo da API Gemini com o schema de resposta e retorna o texto gerado.
+func (s *GeminiPuzzleService) generateContent(ctx context.Context, model string, contents []GeminiContent, schema *GeminiSchema) (string, error) {
 	// Constrói o payload da requisição para a API Gemini.
 	geminiReq := GeminiRequest{
-		Contents: []GeminiContent{
-			{
-				Parts: []GeminiContentPart{
-					{Text: prompt},
-				},
-			},
-		},
+		Contents: contents,
 		GenerationConfig: GeminiGenerationConfig{
 			ResponseMimeType: "application/json",
-			ResponseSchema:   responseSchema, // Usa o json.RawMessage validado
+			ResponseSchema:   schema,
 			Temperature:      0.7, // Ajuste conforme necessário para criatividade vs. consistência.
 			TopP:             0.9,
 			TopK:             40,
@@ -202,52 +135,77 @@ func (s *GeminiPuzzleService) GeneratePuzzle(req PuzzleRequest) ([]byte, error)
 	// Serializa a struct Go para um slice de bytes JSON para o corpo da requisição HTTP.
 	jsonReqBody, err := json.Marshal(geminiReq)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao serializar a requisição Gemini: %w", err)
+		return "", fmt.Errorf("falha ao serializar a requisição Gemini: %w", err)
+	}
+
+	prompt := contents[len(contents)-1].Parts[0].Text
+	log.Printf("Chamando a API Gemini (%s) com prompt (truncado): %s...", model, prompt[:min(len(prompt), 100)]) // Registra um prompt truncado para brevidade.
+
+	// Uma resposta 429 coloca a chave em espera e a requisição é repetida com a próxima chave do pool.
+	var (
+		status    int
+		bodyBytes []byte
+	)
+	for i := 0; i < s.keys.size(); i++ {
+		key, err := s.keys.acquire()
+		if err != nil {
+			return "", err
+		}
+		var header http.Header
+		status, header, bodyBytes, err = s.post(ctx, fmt.Sprintf(geminiAPIURL, model), key, jsonReqBody)
+		if err != nil {
+			return "", err
+		}
+		if status != http.StatusTooManyRequests {
+			break
+		}
+		wait := s.keys.throttle(key, parseRetryAfter(header))
+		log.Printf("API Gemini retornou 429 para a %s; em espera por %s.", key, wait)
 	}
 
-	log.Printf("Chamando a API Gemini com prompt (truncado): %s...", prompt[:min(len(prompt), 100)]) // Registra um prompt truncado para brevidade.
-	client := &http.Client{} // Cria um novo cliente HTTP.
+	// Verifica códigos de status HTTP diferentes de 200 do Gemini.
+	if status != http.StatusOK {
+		return "", &GeminiAPIError{StatusCode: status, Body: string(bodyBytes)}
+	}
 
-	// Cria uma nova requisição POST para o endpoint da API Gemini, incluindo a chave da API na string de consulta.
-	httpReq, err := http.NewRequest("POST", fmt.Sprintf("%s?key=%s", geminiAPIURL, s.apiKey), bytes.NewBuffer(jsonReqBody))
+	// Deserializa a resposta da API Gemini para a struct GeminiAPIResponse.
+	var geminiAPIResp GeminiAPIResponse
+	if err := json.Unmarshal(bodyBytes, &geminiAPIResp); err != nil {
+		return "", fmt.Errorf("falha ao deserializar a resposta da API Gemini: %w. Resposta bruta: %s", err, string(bodyBytes))
+	}
+
+	// Trata bloqueios, truncamento e respostas vazias antes de extrair o texto gerado (string JSON).
+	text, err := checkGeminiResponse(&geminiAPIResp, bodyBytes)
+	if err != nil {
+		return "", err
+	}
+	log.Println("Resposta da API Gemini recebida com sucesso.")
+	return text, nil
+}
+
+// post envia o corpo ao endpoint da API Gemini (url) com a chave informada e retorna o status, os cabeçalhos e o corpo da resposta.
+func (s *GeminiPuzzleService) post(ctx context.Context, url string, key *apiKey, body []byte) (int, http.Header, []byte, error) {
+	// A chave vai no cabeçalho x-goog-api-key, e não na URL, para não aparecer em logs de URLs e proxies.
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		return nil, fmt.Errorf("falha ao criar requisição HTTP: %w", err)
+		return 0, nil, nil, fmt.Errorf("falha ao criar requisição HTTP: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json") // Define o cabeçalho do tipo de conteúdo.
+	httpReq.Header.Set("x-goog-api-key", key.value)
 
 	// Executa a requisição HTTP.
-	resp, err := client.Do(httpReq)
+	resp, err := s.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao fazer requisição HTTP para Gemini: %w", err)
+		return 0, nil, nil, fmt.Errorf("falha ao fazer requisição HTTP para Gemini: %w", err)
 	}
 	defer resp.Body.Close() // Garante que o corpo da resposta seja fechado após a leitura.
 
 	// Lê o corpo completo da resposta.
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao ler o corpo da resposta Gemini: %w", err)
-	}
-
-	// Verifica códigos de status HTTP diferentes de 200 do Gemini.
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API Gemini falhou com status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	// Deserializa a resposta da API Gemini para a struct GeminiAPIResponse.
-	var geminiAPIResp GeminiAPIResponse
-	if err := json.Unmarshal(bodyBytes, &geminiAPIResp); err != nil {
-		return nil, fmt.Errorf("falha ao deserializar a resposta da API Gemini: %w. Resposta bruta: %s", err, string(bodyBytes))
-	}
-
-	// Valida se a resposta contém candidatos e conteúdo.
-	if len(geminiAPIResp.Candidates) == 0 || len(geminiAPIResp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("A resposta da API Gemini estava vazia ou inesperada. Resposta bruta: %s", string(bodyBytes))
+		return 0, nil, nil, fmt.Errorf("falha ao ler o corpo da resposta Gemini: %w", err)
 	}
-
-	// Extrai o texto gerado (string JSON) da resposta Gemini.
-	jsonString := geminiAPIResp.Candidates[0].Content.Parts[0].Text
-	log.Println("Resposta da API Gemini recebida com sucesso.")
-	return []byte(jsonString), nil // Retorna a string JSON bruta do Gemini.
+	return resp.StatusCode, resp.Header, bodyBytes, nil
 }
 
 // min é uma função auxiliar para obter o mínimo de dois inteiros.
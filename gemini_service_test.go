@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGeminiReply é uma resposta programada do fakeGemini.
+type fakeGeminiReply struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// fakeGeminiCall é uma requisição recebida pelo fakeGemini.
+type fakeGeminiCall struct {
+	url     string
+	model   string // Extraído do caminho .../models/<modelo>:generateContent
+	key     string // Cabeçalho x-goog-api-key
+	request GeminiRequest
+}
+
+// fakeGemini é o Transport do cliente da API Gemini nos testes: registra as requisições e devolve as
+// respostas programadas, em ordem.
+type fakeGemini struct {
+	mu      sync.Mutex
+	replies []fakeGeminiReply
+	calls   []fakeGeminiCall
+}
+
+func (f *fakeGemini) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	call := fakeGeminiCall{url: r.URL.String(), key: r.Header.Get("x-goog-api-key")}
+	call.model, _, _ = strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1beta/models/"), ":")
+	if err := json.Unmarshal(body, &call.request); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+	if len(f.replies) == 0 {
+		return nil, fmt.Errorf("requisição %d sem resposta programada", len(f.calls))
+	}
+	reply := f.replies[0]
+	f.replies = f.replies[1:]
+	header := reply.header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: reply.status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(reply.body)),
+		Request:    r,
+	}, nil
+}
+
+// geminiText é a resposta da API Gemini com o texto gerado e finishReason STOP.
+func geminiText(text string) fakeGeminiReply {
+	body, _ := json.Marshal(GeminiAPIResponse{Candidates: []GeminiCandidate{{
+		Content:      GeminiContent{Role: "model", Parts: []GeminiContentPart{{Text: text}}},
+		FinishReason: "STOP",
+	}}})
+	return fakeGeminiReply{status: http.StatusOK, body: string(body)}
+}
+
+const (
+	testCrossword        = `{"gameType":"crossword","difficulty":"easy","topics":["animais"],"crosswordData":{"gridSize":{"rows":5,"cols":5},"words":[{"word":"GATO","clue":"Felino","startRow":0,"startCol":0,"direction":"across"}]}}`
+	testInvalidCrossword = `{"gameType":"crossword","difficulty":"easy","topics":["animais"],"crosswordData":{"gridSize":{"rows":5,"cols":5},"words":[{"word":"CAVALO","clue":"Equino","startRow":0,"startCol":0,"direction":"across"}]}}`
+)
+
+// testCrosswordRequest é a requisição usada nos testes do serviço Gemini.
+var testCrosswordRequest = PuzzleRequest{GameType: "crossword", Difficulty: "easy", Topics: []string{"animais"}, Language: "pt"}
+
+// newTestGeminiService cria o serviço com o transporte falso, as chaves e a cadeia de modelos informados.
+func newTestGeminiService(t *testing.T, fake *fakeGemini, keys []string, models []string, maxAttempts int) *GeminiPuzzleService {
+	t.Helper()
+	prompts, err := LoadPrompts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGeminiPuzzleService(GeminiConfig{
+		APIKeys:     keys,
+		KeyCooldown: time.Minute,
+		MaxAttempts: maxAttempts,
+		Models:      ModelChains{"": models},
+		HTTP:        UpstreamHTTPConfig{Timeout: 5 * time.Second, Transport: fake},
+	}, prompts)
+}
+
+// generateTestCrossword chama GeneratePuzzle com testCrosswordRequest.
+func generateTestCrossword(s *GeminiPuzzleService) ([]byte, string, error) {
+	gt, _ := lookupGameType("crossword")
+	return s.GeneratePuzzle(context.Background(), gt, testCrosswordRequest)
+}
+
+func TestGeminiSendsAPIKeyInHeader(t *testing.T) {
+	fake := &fakeGemini{replies: []fakeGeminiReply{geminiText(testCrossword)}}
+	s := newTestGeminiService(t, fake, []string{"chave-secreta"}, []string{"gemini-teste"}, 1)
+
+	if _, _, err := generateTestCrossword(s); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.calls) != 1 {
+		t.Fatalf("%d chamadas à API, quer 1", len(fake.calls))
+	}
+	call := fake.calls[0]
+	if call.key != "chave-secreta" {
+		t.Errorf("x-goog-api-key = %q, quer a chave configurada", call.key)
+	}
+	if strings.Contains(call.url, "chave-secreta") || strings.Contains(call.url, "key=") {
+		t.Errorf("a chave aparece na URL: %s", call.url)
+	}
+	if call.model != "gemini-teste" {
+		t.Errorf("modelo = %q, quer gemini-teste", call.model)
+	}
+}
+
+// Um 429 coloca a chave em espera (pelo Retry-After) e a mesma requisição é repetida com a próxima
+// chave; enquanto a espera dura, a chave não é mais usada.
+func TestGemini429ThrottlesKeyAndUsesNext(t *testing.T) {
+	fake := &fakeGemini{replies: []fakeGeminiReply{
+		{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"120"}}, body: `{"error":{"code":429}}`},
+		geminiText(testCrossword),
+		geminiText(testCrossword),
+	}}
+	s := newTestGeminiService(t, fake, []string{"chave-1", "chave-2"}, []string{"gemini-teste"}, 1)
+
+	if _, _, err := generateTestCrossword(s); err != nil {
+		t.Fatalf("GeneratePuzzle = %v, quer sucesso com a segunda chave", err)
+	}
+	if _, _, err := generateTestCrossword(s); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, call := range fake.calls {
+		keys = append(keys, call.key)
+	}
+	if want := []string{"chave-1", "chave-2", "chave-2"}; strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("chaves usadas = %v, quer %v", keys, want)
+	}
+	if wait := time.Until(s.keys.keys[0].throttledUntil); wait < 100*time.Second {
+		t.Errorf("chave 1 em espera por %s, quer o Retry-After de 120s", wait)
+	}
+}
+
+// finishReason e promptFeedback decidem o status da resposta: bloqueios são 422, o resto é 502.
+func TestGeminiResponseStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  fakeGeminiReply
+		status int
+	}{
+		{"prompt bloqueado", fakeGeminiReply{status: http.StatusOK, body: `{"promptFeedback":{"blockReason":"SAFETY"}}`}, http.StatusUnprocessableEntity},
+		{"candidato bloqueado", fakeGeminiReply{status: http.StatusOK, body: `{"candidates":[{"content":{"parts":[]},"finishReason":"SAFETY"}]}`}, http.StatusUnprocessableEntity},
+		{"recitação", fakeGeminiReply{status: http.StatusOK, body: `{"candidates":[{"content":{"parts":[]},"finishReason":"RECITATION"}]}`}, http.StatusUnprocessableEntity},
+		{"truncada", fakeGeminiReply{status: http.StatusOK, body: `{"candidates":[{"content":{"parts":[{"text":"{\"game"}]},"finishReason":"MAX_TOKENS"}]}`}, http.StatusBadGateway},
+		{"outro motivo", fakeGeminiReply{status: http.StatusOK, body: `{"candidates":[{"content":{"parts":[]},"finishReason":"OTHER"}]}`}, http.StatusBadGateway},
+		{"sem candidatos", fakeGeminiReply{status: http.StatusOK, body: `{"candidates":[]}`}, http.StatusBadGateway},
+		{"erro da API", fakeGeminiReply{status: http.StatusInternalServerError, body: `{"error":{"code":500}}`}, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGemini{replies: []fakeGeminiReply{tt.reply}}
+			s := newTestGeminiService(t, fake, []string{"chave"}, []string{"gemini-teste"}, 3)
+
+			_, _, err := generateTestCrossword(s)
+			if err == nil {
+				t.Fatal("GeneratePuzzle sem erro")
+			}
+			if status, _ := generationErrorStatus(err); status != tt.status {
+				t.Errorf("status = %d, quer %d (erro: %v)", status, tt.status, err)
+			}
+			if len(fake.calls) != 1 {
+				t.Errorf("%d chamadas à API, quer 1 (a resposta não é corrigível)", len(fake.calls))
+			}
+		})
+	}
+}
+
+// Uma resposta que quebra as regras do tipo de jogo continua a conversa: a segunda chamada envia o
+// prompt original, a resposta inválida e o prompt de reparo com o problema encontrado.
+func TestGeminiRepairRoundTrip(t *testing.T) {
+	fake := &fakeGemini{replies: []fakeGeminiReply{geminiText(testInvalidCrossword), geminiText(testCrossword)}}
+	s := newTestGeminiService(t, fake, []string{"chave"}, []string{"gemini-teste"}, 2)
+
+	puzzle, _, err := generateTestCrossword(s)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle = %v, quer o quebra-cabeça corrigido", err)
+	}
+	if !bytes.Contains(puzzle, []byte(`"GATO"`)) {
+		t.Errorf("quebra-cabeça = %s, quer a resposta corrigida", puzzle)
+	}
+	if len(fake.calls) != 2 {
+		t.Fatalf("%d chamadas à API, quer 2", len(fake.calls))
+	}
+
+	contents := fake.calls[1].request.Contents
+	if len(contents) != 3 {
+		t.Fatalf("a conversa de reparo tem %d turnos, quer 3", len(contents))
+	}
+	if contents[0].Parts[0].Text != fake.calls[0].request.Contents[0].Parts[0].Text {
+		t.Error("o primeiro turno do reparo não é o prompt original")
+	}
+	if contents[1].Role != "model" || contents[1].Parts[0].Text != testInvalidCrossword {
+		t.Errorf("segundo turno = %s %q, quer a resposta inválida do modelo", contents[1].Role, contents[1].Parts[0].Text)
+	}
+	if repair := contents[2].Parts[0].Text; contents[2].Role != "user" || !strings.Contains(repair, `palavra "CAVALO"`) {
+		t.Errorf("terceiro turno = %s %q, quer o prompt de reparo com o problema", contents[2].Role, repair)
+	}
+}
+
+func TestGeminiModelFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		replies   []fakeGeminiReply
+		wantModel string   // Modelo que gerou o quebra-cabeça; vazio quando a geração falha
+		called    []string // Modelos chamados, em ordem
+	}{
+		{
+			name:      "erro da API passa para o próximo modelo",
+			replies:   []fakeGeminiReply{{status: http.StatusInternalServerError, body: `{}`}, geminiText(testCrossword)},
+			wantModel: "gemini-b",
+			called:    []string{"gemini-a", "gemini-b"},
+		},
+		{
+			name:      "resposta inválida após as tentativas passa para o próximo modelo",
+			replies:   []fakeGeminiReply{geminiText(testInvalidCrossword), geminiText(testCrossword)},
+			wantModel: "gemini-b",
+			called:    []string{"gemini-a", "gemini-b"},
+		},
+		{
+			name:    "bloqueio não passa para o próximo modelo",
+			replies: []fakeGeminiReply{{status: http.StatusOK, body: `{"promptFeedback":{"blockReason":"SAFETY"}}`}},
+			called:  []string{"gemini-a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGemini{replies: tt.replies}
+			s := newTestGeminiService(t, fake, []string{"chave"}, []string{"gemini-a", "gemini-b"}, 1)
+
+			_, model, err := generateTestCrossword(s)
+			if tt.wantModel == "" {
+				if err == nil {
+					t.Error("GeneratePuzzle sem erro")
+				}
+			} else if err != nil || model != tt.wantModel {
+				t.Errorf("GeneratePuzzle = (%q, %v), quer o modelo %q", model, err, tt.wantModel)
+			}
+
+			var called []string
+			for _, call := range fake.calls {
+				called = append(called, call.model)
+			}
+			if strings.Join(called, ",") != strings.Join(tt.called, ",") {
+				t.Errorf("modelos chamados = %v, quer %v", called, tt.called)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"puzzle_proxy_api/puzzlepb"
+)
+
+// puzzleGRPCServer implementa puzzlepb.PuzzleServiceServer sobre a mesma lógica de cache e geração
+// dos endpoints HTTP.
+type puzzleGRPCServer struct {
+	puzzlepb.UnimplementedPuzzleServiceServer
+	server *Server
+}
+
+// serveGRPC escuta na porta informada e atende a API gRPC até ocorrer um erro.
+func (s *Server) serveGRPC(port string) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("falha ao escutar na porta gRPC %s: %w", port, err)
+	}
+	gs := grpc.NewServer()
+	puzzlepb.RegisterPuzzleServiceServer(gs, &puzzleGRPCServer{server: s})
+	reflection.Register(gs) // Permite inspecionar o serviço com ferramentas como grpcurl.
+	return gs.Serve(lis)
+}
+
+// GeneratePuzzle gera o quebra-cabeça com o contexto da chamada: se o cliente cancelar ou o prazo
+// acabar, a chamada ao Gemini em andamento é interrompida.
+func (g *puzzleGRPCServer) GeneratePuzzle(ctx context.Context, req *puzzlepb.PuzzleRequest) (*puzzlepb.Puzzle, error) {
+	if req.GetCallbackUrl() != "" {
+		return nil, status.Error(codes.InvalidArgument, "callback_url só é aceito em CreateJob")
+	}
+	puzzle, err := g.server.generate(ctx, puzzleRequestFromProto(req))
+	if err != nil {
+		log.Printf("Erro ao gerar quebra-cabeça (gRPC) para a requisição %+v: %v", req, err)
+		return nil, grpcError(err)
+	}
+	return puzzleToProto(puzzle.Data, puzzle.Cached)
+}
+
+func (g *puzzleGRPCServer) GetPuzzle(ctx context.Context, req *puzzlepb.GetPuzzleRequest) (*puzzlepb.Puzzle, error) {
+	entry, err := g.server.store.GetCachedPuzzleByID(req.GetPuzzleId())
+	if err != nil {
+		log.Printf("Erro ao obter o quebra-cabeça %s: %v", req.GetPuzzleId(), err)
+		return nil, status.Error(codes.Internal, "falha ao obter o quebra-cabeça")
+	}
+	if entry == nil {
+		return nil, status.Error(codes.NotFound, "quebra-cabeça não encontrado")
+	}
+	return puzzleToProto(newGeneratedPuzzle(entry, true).Data, true)
+}
+
+// CreateJob cria o job como POST /jobs, incluindo a validação de callback_url. A geração continua depois
+// que a chamada termina, então não usa o seu contexto (ver JobManager.run).
+func (g *puzzleGRPCServer) CreateJob(ctx context.Context, req *puzzlepb.PuzzleRequest) (*puzzlepb.Job, error) {
+	if url := req.GetCallbackUrl(); url != "" {
+		if err := g.server.webhooks.validateCallbackURL(url); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	job, err := g.server.jobs.Create(puzzleRequestFromProto(req), req.GetCallbackUrl())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return jobToProto(job)
+}
+
+func (g *puzzleGRPCServer) GetJob(ctx context.Context, req *puzzlepb.GetJobRequest) (*puzzlepb.Job, error) {
+	job, ok := g.server.jobs.Get(req.GetId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "job não encontrado")
+	}
+	return jobToProto(job)
+}
+
+func (g *puzzleGRPCServer) WatchJob(req *puzzlepb.GetJobRequest, stream puzzlepb.PuzzleService_WatchJobServer) error {
+	found, err := g.server.jobs.Watch(stream.Context(), req.GetId(), func(job Job) error {
+		pb, err := jobToProto(job)
+		if err != nil {
+			return err
+		}
+		return stream.Send(pb)
+	})
+	if !found {
+		return status.Error(codes.NotFound, "job não encontrado")
+	}
+	return err
+}
+
+// grpcError converte um erro de geração no status gRPC equivalente ao status HTTP de generationErrorStatus.
+// Cancelamentos e prazos esgotados da chamada viram Canceled e DeadlineExceeded.
+func grpcError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	httpStatus, msg := generationErrorStatus(err)
+	code := codes.Internal
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = codes.InvalidArgument
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusBadGateway:
+		code = codes.Unavailable
+	}
+	return status.Error(code, msg)
+}
+
+// puzzleRequestFromProto converte a requisição gRPC para o modelo usado no cache.
+func puzzleRequestFromProto(req *puzzlepb.PuzzleRequest) PuzzleRequest {
+	return PuzzleRequest{
+		GameType:   req.GetGameType(),
+		Difficulty: req.GetDifficulty(),
+		Topics:     req.GetTopics(),
+		Language:   req.GetLanguage(),
+	}
+}
+
+// puzzleToProto converte o JSON do quebra-cabeça para a mensagem Puzzle. Os campos das mensagens têm os
+// mesmos nomes JSON dos modelos Go, então a conversão é feita pelo protojson; a exceção são as grades do
+// sudoku (ver sudokuGridsToRows).
+func puzzleToProto(data []byte, cached bool) (*puzzlepb.Puzzle, error) {
+	var puzzle puzzlepb.Puzzle
+	data, err := sudokuGridsToRows(data)
+	if err == nil {
+		err = (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, &puzzle)
+	}
+	if err != nil {
+		log.Printf("Erro ao converter o quebra-cabeça para protobuf: %v", err)
+		return nil, status.Error(codes.Internal, "falha ao converter o quebra-cabeça")
+	}
+	puzzle.Cached = cached
+	return &puzzle, nil
+}
+
+// sudokuGridsToRows reescreve as grades puzzle e solution de sudokuData, que no JSON são arrays de
+// arrays, no formato protojson das mensagens SudokuRow ({"cells": [...]} por linha). Os demais tipos de
+// jogo são retornados sem alteração.
+func sudokuGridsToRows(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	raw, ok := fields["sudokuData"]
+	if !ok {
+		return data, nil
+	}
+	var sudoku map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sudoku); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"puzzle", "solution"} {
+		var grid [][]int
+		if err := json.Unmarshal(sudoku[name], &grid); err != nil {
+			return nil, fmt.Errorf("sudokuData.%s: %w", name, err)
+		}
+		rows := make([]map[string][]int, len(grid))
+		for i, cells := range grid {
+			rows[i] = map[string][]int{"cells": cells}
+		}
+		sudoku[name], _ = json.Marshal(rows)
+	}
+	fields["sudokuData"], _ = json.Marshal(sudoku)
+	return json.Marshal(fields)
+}
+
+// jobToProto converte o job para a mensagem Job.
+func jobToProto(job Job) (*puzzlepb.Job, error) {
+	pb := &puzzlepb.Job{
+		Id:     job.ID,
+		Status: jobStatusToProto[job.Status],
+		Request: &puzzlepb.PuzzleRequest{
+			GameType:    job.Request.GameType,
+			Difficulty:  job.Request.Difficulty,
+			Topics:      job.Request.Topics,
+			Language:    job.Request.Language,
+			CallbackUrl: job.CallbackURL,
+		},
+		Error:       job.Error,
+		ErrorStatus: int32(job.ErrorStatus),
+		CreatedAt:   timestamppb.New(job.CreatedAt),
+		UpdatedAt:   timestamppb.New(job.UpdatedAt),
+	}
+	if job.Puzzle != nil {
+		puzzle, err := puzzleToProto(job.Puzzle, job.Cached)
+		if err != nil {
+			return nil, err
+		}
+		pb.Puzzle = puzzle
+	}
+	return pb, nil
+}
+
+var jobStatusToProto = map[JobStatus]puzzlepb.JobStatus{
+	JobPending:   puzzlepb.JobStatus_JOB_STATUS_PENDING,
+	JobRunning:   puzzlepb.JobStatus_JOB_STATUS_RUNNING,
+	JobSucceeded: puzzlepb.JobStatus_JOB_STATUS_SUCCEEDED,
+	JobFailed:    puzzlepb.JobStatus_JOB_STATUS_FAILED,
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"puzzle_proxy_api/puzzlepb"
+)
+
+// blockingTransport segura cada requisição até o contexto dela ser cancelado.
+type blockingTransport struct {
+	started chan struct{}
+}
+
+func (b *blockingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	close(b.started)
+	<-r.Context().Done()
+	return nil, r.Context().Err()
+}
+
+// Cancelar a chamada gRPC deve interromper a requisição ao Gemini em andamento.
+func TestGRPCGeneratePuzzleCancelsUpstream(t *testing.T) {
+	prompts, err := LoadPrompts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &blockingTransport{started: make(chan struct{})}
+	s := &Server{
+		store: NewMemoryStore(),
+		geminiPuzzleService: NewGeminiPuzzleService(GeminiConfig{
+			APIKeys:     []string{"chave"},
+			MaxAttempts: 1,
+			Models:      ModelChains{"": {"gemini-a", "gemini-b"}},
+			HTTP:        UpstreamHTTPConfig{Transport: transport},
+		}, prompts),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-transport.started
+		cancel()
+	}()
+	done := make(chan error, 1)
+	go func() {
+		_, err := (&puzzleGRPCServer{server: s}).GeneratePuzzle(ctx, &puzzlepb.PuzzleRequest{GameType: "crossword", Difficulty: "easy", Language: "pt"})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Canceled {
+			t.Errorf("GeneratePuzzle = %v, quer codes.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GeneratePuzzle não terminou depois do cancelamento")
+	}
+}
+
+func TestGRPCCreateJob(t *testing.T) {
+	s := &Server{store: NewMemoryStore()}
+	s.webhooks = newWebhookSender(WebhookConfig{Secret: testWebhookSecret, Timeout: time.Second, MaxAttempts: 1}, s.store)
+	s.jobs = NewJobManager(JobsConfig{MaxConcurrent: 1, Retention: time.Hour}, func(context.Context, PuzzleRequest) (*generatedPuzzle, error) {
+		return nil, &GeminiBlockedError{Reason: "SAFETY"}
+	}, nil)
+	g := &puzzleGRPCServer{server: s}
+
+	_, err := g.CreateJob(context.Background(), &puzzlepb.PuzzleRequest{GameType: "crossword", CallbackUrl: "http://169.254.169.254/latest"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateJob com callback interno = %v, quer codes.InvalidArgument", err)
+	}
+	if _, err := g.GeneratePuzzle(context.Background(), &puzzlepb.PuzzleRequest{GameType: "crossword", CallbackUrl: "https://example.com/hook"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GeneratePuzzle com callback_url = %v, quer codes.InvalidArgument", err)
+	}
+
+	job, err := g.CreateJob(context.Background(), &puzzlepb.PuzzleRequest{GameType: "crossword", CallbackUrl: "https://example.com/hook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.GetRequest().GetCallbackUrl() != "https://example.com/hook" {
+		t.Errorf("request.callback_url = %q, quer o endereço informado", job.GetRequest().GetCallbackUrl())
+	}
+
+	var last *puzzlepb.Job
+	if _, err := s.jobs.Watch(context.Background(), job.GetId(), func(j Job) error {
+		pb, err := jobToProto(j)
+		last = pb
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if last.GetStatus() != puzzlepb.JobStatus_JOB_STATUS_FAILED || last.GetErrorStatus() != http.StatusUnprocessableEntity {
+		t.Errorf("job = %v com error_status %d, quer JOB_STATUS_FAILED com 422", last.GetStatus(), last.GetErrorStatus())
+	}
+}
+
+// As grades do sudoku chegam ao gRPC como linhas SudokuRow, com os mesmos números do JSON da API HTTP.
+func TestGRPCSudokuRows(t *testing.T) {
+	s := &Server{store: NewMemoryStore()}
+	pb, err := (&puzzleGRPCServer{server: s}).GeneratePuzzle(context.Background(), &puzzlepb.PuzzleRequest{GameType: "sudoku", Difficulty: "easy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := s.store.GetCachedPuzzleByID(pb.GetPuzzleId())
+	if err != nil || entry == nil {
+		t.Fatalf("quebra-cabeça %s não está no cache: %v", pb.GetPuzzleId(), err)
+	}
+	var want struct {
+		SudokuData SudokuData `json:"sudokuData"`
+	}
+	if err := json.Unmarshal(entry.ResponseData, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	sudoku := pb.GetSudokuData()
+	for name, grid := range map[string]struct {
+		rows []*puzzlepb.SudokuRow
+		want [][]int
+	}{"puzzle": {sudoku.GetPuzzle(), want.SudokuData.Puzzle}, "solution": {sudoku.GetSolution(), want.SudokuData.Solution}} {
+		if len(grid.rows) != 9 {
+			t.Fatalf("%s com %d linhas, quer 9", name, len(grid.rows))
+		}
+		for i, row := range grid.rows {
+			cells := make([]int, len(row.GetCells()))
+			for j, cell := range row.GetCells() {
+				cells[j] = int(cell)
+			}
+			if !reflect.DeepEqual(cells, grid.want[i]) {
+				t.Fatalf("%s linha %d = %v, quer %v", name, i, cells, grid.want[i])
+			}
+		}
+	}
+	if int(sudoku.GetGivens()) != want.SudokuData.Givens {
+		t.Errorf("givens = %d, quer %d", sudoku.GetGivens(), want.SudokuData.Givens)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// etagFor deriva um ETag fraco do conteúdo. É fraco porque o mesmo corpo pode ser enviado comprimido ou não.
+func etagFor(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// etagMatches informa se o cabeçalho If-None-Match contém o ETag, usando a comparação fraca da RFC 9110.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCacheable escreve o corpo JSON com o ETag informado. Em GET e HEAD, se o cliente já tiver essa
+// versão (If-None-Match), responde 304 sem corpo.
+func writeCacheable(w http.ResponseWriter, r *http.Request, etag string, body []byte) {
+	w.Header().Set("ETag", etag)
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// writeCacheableJSON serializa v e o escreve com writeCacheable, usando como ETag o hash do corpo serializado.
+// Assim, campos que variam entre respostas da mesma versão do quebra-cabeça (como cached, nos endpoints /v1)
+// também mudam o ETag, e um 304 só é enviado quando o corpo seria idêntico ao que o cliente já tem.
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Erro ao serializar a resposta JSON: %v", err)
+		http.Error(w, "Erro interno do servidor.", http.StatusInternalServerError)
+		return
+	}
+	writeCacheable(w, r, etagFor(body), body)
+}
+
+// withCacheControl define o Cache-Control nas respostas de sucesso e 304 do manipulador. Respostas de erro
+// não o recebem, para que um 404 ou 503 não fique guardado em caches intermediários.
+func withCacheControl(cacheControl string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(&cacheControlWriter{ResponseWriter: w, cacheControl: cacheControl}, r)
+	}
+}
+
+// cacheControlWriter adiciona o Cache-Control quando o status da resposta é conhecido.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	cacheControl string
+	wroteHeader  bool
+}
+
+func (cw *cacheControlWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if status < 300 || status == http.StatusNotModified {
+			cw.Header().Set("Cache-Control", cw.cacheControl)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheControlWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Codificações de compressão suportadas, na ordem de preferência em caso de empate no Accept-Encoding.
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// negotiateEncoding escolhe a codificação a partir do Accept-Encoding, respeitando os pesos (q).
+// Retorna "" se o cliente não aceitar nenhuma das suportadas.
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		if q := encodingQuality(acceptEncoding, encoding); q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// encodingQuality retorna o peso da codificação no Accept-Encoding (0 se ausente ou recusada).
+// Uma entrada explícita tem precedência sobre "*".
+func encodingQuality(acceptEncoding, encoding string) float64 {
+	q, wildcard := -1.0, 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = parsed
+			}
+		}
+		if name == "*" {
+			wildcard = weight
+		} else {
+			q = weight
+		}
+	}
+	if q < 0 {
+		return wildcard
+	}
+	return q
+}
+
+// compressMiddleware comprime as respostas com brotli ou gzip conforme o Accept-Encoding do cliente.
+// Respostas menores que minSize, sem corpo (204, 304, HEAD) ou já codificadas são enviadas como estão.
+func compressMiddleware(minSize int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// compressWriter acumula o início da resposta até saber se ela atinge minSize; a partir daí, passa a
+// escrever pelo compressor. O status só é enviado quando a decisão é tomada, pois depende dos cabeçalhos.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool           // WriteHeader foi chamado pelo manipulador
+	decided     bool           // Os cabeçalhos já foram enviados ao cliente
+	buf         []byte         // Início da resposta, enquanto menor que minSize
+	encoder     io.WriteCloser // Compressor; nil se a resposta não for comprimida
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader, cw.status = true, status
+	// Respostas sem corpo ou já codificadas pelo manipulador não passam pelo compressor.
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified || cw.Header().Get("Content-Encoding") != "" {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < cw.minSize {
+			return len(p), nil
+		}
+		buf := cw.buf
+		cw.buf = nil
+		cw.decide(true)
+		if _, err := cw.encoder.Write(buf); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide envia os cabeçalhos e, se compress for verdadeiro, inicia o compressor.
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	if compress {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+		switch cw.encoding {
+		case encodingBrotli:
+			cw.encoder = brotli.NewWriterLevel(cw.ResponseWriter, brotli.DefaultCompression)
+		default:
+			cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+// Close envia o que ficou acumulado (respostas menores que minSize) ou finaliza o compressor.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			return nil // O manipulador não escreveu nada; o net/http envia o 200 padrão.
+		}
+		cw.decide(false)
+		_, err := cw.ResponseWriter.Write(cw.buf)
+		return err
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Close()
+	}
+	return nil
+}
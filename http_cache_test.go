@@ -0,0 +1,184 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestWriteCacheable(t *testing.T) {
+	body := []byte(`{"gameType":"sudoku"}`)
+	etag := etagFor(body)
+	if !regexp.MustCompile(`^W/"[0-9a-f]{16}"$`).MatchString(etag) {
+		t.Fatalf("etagFor = %s, quer um ETag fraco com 16 dígitos hexadecimais", etag)
+	}
+	if etagFor([]byte(`{"gameType":"quiz"}`)) == etag {
+		t.Error("conteúdos diferentes com o mesmo ETag")
+	}
+
+	tests := []struct {
+		method, ifNoneMatch string
+		want                int
+	}{
+		{http.MethodGet, "", http.StatusOK},
+		{http.MethodGet, etag, http.StatusNotModified},
+		{http.MethodGet, strings.TrimPrefix(etag, "W/"), http.StatusNotModified}, // Comparação fraca
+		{http.MethodGet, `W/"outro", ` + etag, http.StatusNotModified},
+		{http.MethodGet, "*", http.StatusNotModified},
+		{http.MethodGet, `W/"outro"`, http.StatusOK},
+		{http.MethodHead, etag, http.StatusNotModified},
+		{http.MethodPost, etag, http.StatusOK}, // If-None-Match só vale para GET e HEAD
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/puzzles/x", nil)
+		if tt.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		writeCacheable(rec, req, etag, body)
+
+		if rec.Code != tt.want || rec.Header().Get("ETag") != etag {
+			t.Errorf("%s If-None-Match %q: status %d com ETag %q, quer %d com %s", tt.method, tt.ifNoneMatch, rec.Code, rec.Header().Get("ETag"), tt.want, etag)
+		}
+		wantBody := string(body)
+		if tt.want == http.StatusNotModified {
+			wantBody = ""
+		}
+		if rec.Body.String() != wantBody {
+			t.Errorf("%s If-None-Match %q: corpo %q, quer %q", tt.method, tt.ifNoneMatch, rec.Body, wantBody)
+		}
+	}
+}
+
+// O Cache-Control vem da rota ou de CACHE_CONTROL_<NAME> e só é enviado nas respostas de sucesso e 304.
+func TestCacheControlPerEndpoint(t *testing.T) {
+	s := &Server{
+		store:     NewMemoryStore(),
+		httpCache: HTTPCacheConfig{CacheControl: map[string]string{"getopenapi": "public, max-age=60"}},
+		jobs:      NewJobManager(JobsConfig{MaxConcurrent: 1}, nil, nil),
+	}
+	handler := s.routes()
+	puzzle, err := s.generate(context.Background(), PuzzleRequest{GameType: "sudoku", Difficulty: "easy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target, ifNoneMatch string
+		wantStatus          int
+		want                string
+	}{
+		{"/openapi.json", "", http.StatusOK, "public, max-age=60"}, // Substituído pela configuração
+		{"/openapi.json", "*", http.StatusNotModified, "public, max-age=60"},
+		{"/puzzles/" + puzzle.PuzzleID, "", http.StatusOK, "public, max-age=86400"}, // Padrão da rota
+		{"/puzzles/desconhecido", "", http.StatusNotFound, ""},
+		{"/v1/puzzles/desconhecido", "", http.StatusNotFound, ""},
+		{"/jobs/desconhecido", "", http.StatusNotFound, ""}, // Padrão da rota: no-store
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.wantStatus || rec.Header().Get("Cache-Control") != tt.want {
+			t.Errorf("GET %s: status %d com Cache-Control %q, quer %d com %q", tt.target, rec.Code, rec.Header().Get("Cache-Control"), tt.wantStatus, tt.want)
+		}
+	}
+
+	for status, want := range map[int]string{http.StatusOK: "no-store", http.StatusNotModified: "no-store", http.StatusBadRequest: "", http.StatusServiceUnavailable: ""} {
+		rec := httptest.NewRecorder()
+		withCacheControl("no-store", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		})(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := rec.Header().Get("Cache-Control"); got != want {
+			t.Errorf("status %d: Cache-Control %q, quer %q", status, got, want)
+		}
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding, want string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", encodingGzip},
+		{"br", encodingBrotli},
+		{"gzip, br", encodingBrotli}, // Empate: brotli é o preferido
+		{"br;q=0.5, gzip", encodingGzip},
+		{"br;q=1.0, gzip;q=0.8", encodingBrotli},
+		{"gzip;q=0, br;q=0", ""},
+		{"*", encodingBrotli},
+		{"*;q=0.5, gzip", encodingGzip},
+		{"*, br;q=0", encodingGzip}, // Entrada explícita tem precedência sobre *
+		{"GZIP", encodingGzip},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.acceptEncoding); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, quer %q", tt.acceptEncoding, got, tt.want)
+		}
+	}
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"word":"ELEFANTE"},`, 100)
+	handler := func(body string) http.Handler {
+		return compressMiddleware(256, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			io.WriteString(w, body)
+		}))
+	}
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"":             func(r io.Reader) (io.Reader, error) { return r, nil },
+		encodingGzip:   func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		encodingBrotli: func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+
+	tests := []struct {
+		name, acceptEncoding, body, wantEncoding string
+	}{
+		{"brotli", "gzip, br", large, encodingBrotli},
+		{"gzip pelo peso", "br;q=0.1, gzip", large, encodingGzip},
+		{"sem Accept-Encoding", "", large, ""},
+		{"menor que minSize", "gzip, br", `{"ok":true}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/puzzles/x", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler(tt.body).ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, quer %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, quer Accept-Encoding", got)
+			}
+			if got := rec.Header().Get("Content-Length"); (got == "") != (tt.wantEncoding != "") {
+				t.Errorf("Content-Length = %q com Content-Encoding %q", got, tt.wantEncoding)
+			}
+			r, err := decoders[tt.wantEncoding](rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(r)
+			if err != nil || string(body) != tt.body {
+				t.Errorf("corpo decodificado = %q (%v), quer o original", body, err)
+			}
+		})
+	}
+}
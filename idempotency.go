@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// maxIdempotencyKeyLength limita o tamanho do cabeçalho Idempotency-Key (UUIDs têm 36 caracteres).
+const maxIdempotencyKeyLength = 255
+
+// idempotencyReplayHeaders são os cabeçalhos da resposta original reenviados na repetição.
+var idempotencyReplayHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// withIdempotency implementa o cabeçalho Idempotency-Key nos endpoints de geração e de jobs. A primeira
+// resposta a uma chave é guardada no store; repetições com a mesma chave e o mesmo corpo dentro da retenção
+// recebem a resposta guardada (com "Idempotent-Replayed: true") sem gerar outro quebra-cabeça nem criar
+// outro job. Reutilizar a chave com outro corpo resulta em 422. Respostas 5xx não são guardadas, para que
+// uma nova tentativa possa ter sucesso. Sem o cabeçalho, o manipulador é chamado normalmente.
+func (s *Server) withIdempotency(operation string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, fmt.Sprintf("Idempotency-Key deve ter no máximo %d caracteres.", maxIdempotencyKeyLength), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Falha ao ler o corpo da requisição: %v", err), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(sum[:])
+
+		// Repetições simultâneas da mesma chave esperam a primeira terminar e depois recebem a resposta guardada.
+		unlock := s.idempotencyLocks.Lock(idempotencyMapKey(operation, key))
+		defer unlock()
+
+		stored, err := s.store.GetIdempotentResponse(operation, key)
+		if err != nil {
+			// Uma falha no store não deve bloquear a requisição; ela apenas deixa de ser idempotente.
+			log.Printf("Erro ao consultar a Idempotency-Key %q de %s: %v", key, operation, err)
+		}
+		if stored != nil && time.Since(stored.CreatedAt) < s.idempotencyRetention {
+			if stored.RequestHash != requestHash {
+				http.Error(w, "Idempotency-Key já usada com outro corpo de requisição.", http.StatusUnprocessableEntity)
+				return
+			}
+			log.Printf("Resposta repetida para a Idempotency-Key %q de %s", key, operation)
+			for name, value := range stored.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+
+		resp := &IdempotentResponse{
+			Operation:   operation,
+			Key:         key,
+			RequestHash: requestHash,
+			StatusCode:  rec.status,
+			Header:      map[string]string{},
+			Body:        rec.body.Bytes(),
+			CreatedAt:   time.Now().UTC(),
+		}
+		for _, name := range idempotencyReplayHeaders {
+			if value := w.Header().Get(name); value != "" {
+				resp.Header[name] = value
+			}
+		}
+		if err := s.store.SaveIdempotentResponse(resp); err != nil {
+			log.Printf("Erro ao salvar a Idempotency-Key %q de %s: %v", key, operation, err)
+		}
+		if _, err := s.store.PurgeIdempotentResponses(resp.CreatedAt.Add(-s.idempotencyRetention)); err != nil {
+			log.Printf("Erro ao expurgar as Idempotency-Keys expiradas: %v", err)
+		}
+	}
+}
+
+// responseRecorder repassa a resposta ao cliente e guarda uma cópia do status e do corpo.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader, rec.status = true, status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(p []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// idempotentPost envia um POST com a Idempotency-Key e o corpo informados.
+func idempotentPost(handler http.HandlerFunc, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/generate-puzzle", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", key)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// newIdempotencyTestServer cria o servidor com retenção de uma hora e um manipulador que conta as chamadas
+// e responde com os status de statuses, em ordem (201 depois do último), e o número da chamada no corpo.
+func newIdempotencyTestServer(statuses ...int) (*Server, http.HandlerFunc, *atomic.Int32) {
+	s := &Server{store: NewMemoryStore(), idempotencyRetention: time.Hour}
+	calls := &atomic.Int32{}
+	handler := s.withIdempotency("generatePuzzle", func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		status := http.StatusCreated
+		if n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/jobs/"+strings.Repeat("a", n))
+		w.WriteHeader(status)
+		w.Write([]byte(`{"call":` + strconv.Itoa(n) + `}`))
+	})
+	return s, handler, calls
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	_, handler, calls := newIdempotencyTestServer()
+
+	first := idempotentPost(handler, "chave", `{"gameType":"sudoku"}`)
+	replay := idempotentPost(handler, "chave", `{"gameType":"sudoku"}`)
+	if calls.Load() != 1 {
+		t.Fatalf("manipulador chamado %d vezes, quer 1", calls.Load())
+	}
+	if replay.Code != first.Code || replay.Body.String() != first.Body.String() {
+		t.Errorf("repetição = %d %s, quer %d %s", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	if got := replay.Header().Get("Location"); got != first.Header().Get("Location") {
+		t.Errorf("Location na repetição = %q, quer %q", got, first.Header().Get("Location"))
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" || replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Idempotent-Replayed = %q e %q, quer vazio e true", first.Header().Get("Idempotent-Replayed"), replay.Header().Get("Idempotent-Replayed"))
+	}
+
+	// Outra chave, mesmo corpo: é uma nova operação.
+	if idempotentPost(handler, "outra", `{"gameType":"sudoku"}`); calls.Load() != 2 {
+		t.Errorf("manipulador chamado %d vezes com outra chave, quer 2", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsKeyReuseWithAnotherBody(t *testing.T) {
+	_, handler, calls := newIdempotencyTestServer()
+
+	idempotentPost(handler, "chave", `{"gameType":"sudoku"}`)
+	if rec := idempotentPost(handler, "chave", `{"gameType":"quiz"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("outro corpo: status %d, quer 422", rec.Code)
+	}
+	if rec := idempotentPost(handler, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("chave longa: status %d, quer 400", rec.Code)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("manipulador chamado %d vezes, quer 1", calls.Load())
+	}
+}
+
+// Uma resposta 5xx não é guardada: a nova tentativa com a mesma chave chama o manipulador de novo.
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	_, handler, calls := newIdempotencyTestServer(http.StatusServiceUnavailable)
+
+	if rec := idempotentPost(handler, "chave", `{}`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("primeira tentativa: status %d, quer 503", rec.Code)
+	}
+	rec := idempotentPost(handler, "chave", `{}`)
+	if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" || calls.Load() != 2 {
+		t.Errorf("nova tentativa: status %d, replayed %q, %d chamadas; quer 201 sem repetição e 2 chamadas",
+			rec.Code, rec.Header().Get("Idempotent-Replayed"), calls.Load())
+	}
+}
+
+// Requisições simultâneas com a mesma chave esperam a primeira terminar e recebem a resposta dela.
+func TestIdempotencySerializesConcurrentRequests(t *testing.T) {
+	s := &Server{store: NewMemoryStore(), idempotencyRetention: time.Hour}
+	started, release := make(chan struct{}), make(chan struct{})
+	calls := &atomic.Int32{}
+	handler := s.withIdempotency("createJob", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"job"}`))
+	})
+
+	const n = 5
+	var wg sync.WaitGroup
+	recs := make([]*httptest.ResponseRecorder, n)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		recs[0] = idempotentPost(handler, "chave", `{}`)
+	}()
+	<-started
+	for i := 1; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recs[i] = idempotentPost(handler, "chave", `{}`)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // As repetições devem ficar esperando a primeira requisição.
+	if calls.Load() != 1 {
+		t.Fatalf("manipulador chamado %d vezes enquanto a primeira requisição estava em andamento, quer 1", calls.Load())
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("manipulador chamado %d vezes, quer 1", calls.Load())
+	}
+	for i, rec := range recs {
+		replayed := rec.Header().Get("Idempotent-Replayed") == "true"
+		if rec.Code != http.StatusAccepted || rec.Body.String() != `{"id":"job"}` || replayed != (i > 0) {
+			t.Errorf("requisição %d: %d %s (replayed %v)", i, rec.Code, rec.Body, replayed)
+		}
+	}
+}
+
+// Uma chave guardada há mais que a retenção não é repetida nem conflita, e as expiradas são expurgadas.
+func TestIdempotencyKeyExpires(t *testing.T) {
+	s, handler, calls := newIdempotencyTestServer()
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	for _, key := range []string{"chave", "abandonada"} {
+		if err := s.store.SaveIdempotentResponse(&IdempotentResponse{
+			Operation: "generatePuzzle", Key: key, RequestHash: "outro-corpo",
+			StatusCode: http.StatusOK, Header: map[string]string{}, Body: []byte(`{"call":0}`), CreatedAt: old,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := idempotentPost(handler, "chave", `{"gameType":"sudoku"}`)
+	if rec.Code != http.StatusCreated || calls.Load() != 1 {
+		t.Errorf("chave expirada: status %d com %d chamadas, quer 201 com 1", rec.Code, calls.Load())
+	}
+	if stored, err := s.store.GetIdempotentResponse("generatePuzzle", "abandonada"); err != nil || stored != nil {
+		t.Errorf("chave expirada não expurgada: %+v, %v", stored, err)
+	}
+	if stored, _ := s.store.GetIdempotentResponse("generatePuzzle", "chave"); stored == nil || stored.CreatedAt.Before(time.Now().Add(-time.Minute)) {
+		t.Errorf("chave reutilizada = %+v, quer a nova resposta", stored)
+	}
+}
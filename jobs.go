@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JobStatus é o estado de um job de geração.
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"   // Na fila, aguardando uma vaga de geração
+	JobRunning   JobStatus = "running"   // Gerando o quebra-cabeça
+	JobSucceeded JobStatus = "succeeded" // Quebra-cabeça pronto (e salvo no cache)
+	JobFailed    JobStatus = "failed"    // A geração falhou; ver Error
+)
+
+// done informa se o job terminou.
+func (s JobStatus) done() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// Job é uma geração de quebra-cabeça em segundo plano. O resultado é salvo no cache como em
+// POST /generate-puzzle, então o quebra-cabeça continua disponível em /puzzles/{id} depois que o job expira.
+type Job struct {
+	ID          string          `json:"id"`
+	Status      JobStatus       `json:"status" enum:"pending,running,succeeded,failed"`
+	Request     PuzzleRequest   `json:"request"`
+	PuzzleID    string          `json:"puzzleId,omitempty"`                                                  // Preenchido quando o job termina com sucesso
+	Puzzle      json.RawMessage `json:"puzzle,omitempty" description:"Same format as POST /generate-puzzle"` // Preenchido quando o job termina com sucesso
+	Cached      bool            `json:"cached,omitempty"`                                                    // Se o quebra-cabeça já estava no cache
+	Error       string          `json:"error,omitempty"`                                                     // Mensagem de erro quando o job falha
+	ErrorStatus int             `json:"errorStatus,omitempty"`                                               // Status HTTP equivalente ao erro (ex: 422 para conteúdo bloqueado)
+	CallbackURL string          `json:"callbackUrl,omitempty"`                                               // Recebe o evento do job quando ele termina (ver webhooks.go)
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+
+	envelope *PuzzleEnvelope // Quebra-cabeça no formato dos endpoints /v1 (ver jobV1)
+}
+
+// JobRequest é o corpo de POST /jobs e POST /v1/jobs: os parâmetros do quebra-cabeça e, opcionalmente,
+// o endereço notificado quando o job termina. O callbackUrl não faz parte da chave de cache.
+type JobRequest struct {
+	PuzzleRequest
+	CallbackURL string `json:"callbackUrl,omitempty" description:"URL that receives a signed POST when the job finishes; requires WEBHOOK_SECRET"`
+}
+
+// JobQueueFullError indica que já há MaxPending jobs na fila ou em execução.
+type JobQueueFullError struct {
+	Limit int
+}
+
+func (e *JobQueueFullError) Error() string {
+	return fmt.Sprintf("fila de jobs cheia: %d jobs pendentes ou em execução", e.Limit)
+}
+
+// jobPruneInterval é o intervalo em que runPruner remove os jobs expirados.
+const jobPruneInterval = time.Minute
+
+// jobEntry guarda um job e o canal usado para avisar quem o acompanha. changed é fechado e
+// substituído a cada mudança de estado.
+type jobEntry struct {
+	job     Job
+	changed chan struct{}
+}
+
+// JobManager executa os jobs de geração em segundo plano e os mantém em memória até Retention depois
+// de terminarem. É seguro para uso concorrente.
+type JobManager struct {
+	mu         sync.Mutex
+	jobs       map[string]*jobEntry
+	generate   func(context.Context, PuzzleRequest) (*generatedPuzzle, error)
+	notify     func(Job)     // Chamada com o job terminado (ex: entrega do webhook)
+	slots      chan struct{} // Limita as gerações simultâneas
+	maxPending int           // Limite de jobs não terminados; 0 não limita
+	active     int           // Jobs não terminados (pendentes ou em execução)
+	retention  time.Duration
+}
+
+// NewJobManager cria o gerenciador de jobs; generate é a mesma função usada por POST /generate-puzzle
+// e notify é chamada com cada job terminado.
+func NewJobManager(cfg JobsConfig, generate func(context.Context, PuzzleRequest) (*generatedPuzzle, error), notify func(Job)) *JobManager {
+	return &JobManager{
+		jobs:       map[string]*jobEntry{},
+		generate:   generate,
+		notify:     notify,
+		slots:      make(chan struct{}, max(cfg.MaxConcurrent, 1)),
+		maxPending: cfg.MaxPending,
+		retention:  cfg.Retention,
+	}
+}
+
+// Create valida a requisição, registra um job pendente e inicia a geração em segundo plano. Com MaxPending
+// jobs ainda não terminados, retorna *JobQueueFullError, para que a fila (e as goroutines à espera de uma
+// vaga) não cresça sem limite. callbackURL, se não for vazio, já deve ter sido validado
+// (ver webhookSender.validateCallbackURL).
+func (m *JobManager) Create(req PuzzleRequest, callbackURL string) (Job, error) {
+	if _, err := gameTypeFor(req); err != nil {
+		return Job{}, err
+	}
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	now := time.Now().UTC()
+	m.mu.Lock()
+	if m.maxPending > 0 && m.active >= m.maxPending {
+		m.mu.Unlock()
+		return Job{}, &JobQueueFullError{Limit: m.maxPending}
+	}
+	m.active++
+	entry := &jobEntry{
+		job:     Job{ID: id, Status: JobPending, Request: req, CallbackURL: callbackURL, CreatedAt: now, UpdatedAt: now},
+		changed: make(chan struct{}),
+	}
+	m.jobs[id] = entry
+	job := entry.job // Copiado com mu travado: run pode alterar entry.job assim que começar.
+	m.mu.Unlock()
+
+	go m.run(id, req)
+	return job, nil
+}
+
+// Get retorna o estado atual do job.
+func (m *JobManager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return entry.job, true
+}
+
+// Watch chama send com o estado atual do job e, depois, a cada mudança, até o job terminar, ctx ser
+// cancelado ou send retornar erro. Retorna false se o job não existir.
+func (m *JobManager) Watch(ctx context.Context, id string, send func(Job) error) (bool, error) {
+	for {
+		m.mu.Lock()
+		entry, ok := m.jobs[id]
+		if !ok {
+			m.mu.Unlock()
+			return false, nil
+		}
+		job, changed := entry.job, entry.changed
+		m.mu.Unlock()
+
+		if err := send(job); err != nil {
+			return true, err
+		}
+		if job.Status.done() {
+			return true, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+	}
+}
+
+// run espera uma vaga de geração, gera o quebra-cabeça, registra o resultado e chama notify. O job
+// continua depois que a requisição que o criou termina, então a geração não usa o contexto dela.
+func (m *JobManager) run(id string, req PuzzleRequest) {
+	m.slots <- struct{}{}
+	defer func() { <-m.slots }()
+
+	m.update(id, func(job *Job) { job.Status = JobRunning })
+	puzzle, err := m.generate(context.Background(), req)
+	var job Job
+	if err != nil {
+		log.Printf("Job %s falhou: %v", id, err)
+		status, msg := generationErrorStatus(err)
+		job = m.update(id, func(job *Job) {
+			job.Status, job.Error, job.ErrorStatus = JobFailed, msg, status
+		})
+	} else {
+		job = m.update(id, func(job *Job) {
+			job.Status, job.PuzzleID, job.Puzzle, job.Cached = JobSucceeded, puzzle.PuzzleID, puzzle.Data, puzzle.Cached
+			job.envelope = puzzle.envelope()
+		})
+	}
+	m.mu.Lock()
+	m.active--
+	m.mu.Unlock()
+	if m.notify != nil && job.ID != "" {
+		m.notify(job)
+	}
+}
+
+// update aplica a mudança ao job, avisa quem o acompanha e retorna o novo estado (vazio se o job não existir).
+func (m *JobManager) update(id string, change func(*Job)) Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.jobs[id]
+	if !ok {
+		return Job{}
+	}
+	change(&entry.job)
+	entry.job.UpdatedAt = time.Now().UTC()
+	close(entry.changed)
+	entry.changed = make(chan struct{})
+	return entry.job
+}
+
+// runPruner remove os jobs expirados a cada jobPruneInterval, liberando a memória mesmo quando nenhum
+// job novo é criado.
+func (m *JobManager) runPruner() {
+	ticker := time.NewTicker(jobPruneInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		m.prune(now)
+	}
+}
+
+// prune remove os jobs terminados há mais de retention em relação a now.
+func (m *JobManager) prune(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, entry := range m.jobs {
+		if entry.job.Status.done() && now.Sub(entry.job.UpdatedAt) > m.retention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// newJobID gera um ID aleatório de 16 caracteres hexadecimais.
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("falha ao gerar o ID do job: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// createJobHandler é o manipulador de POST /jobs. Responde 202 com o job pendente; o cliente acompanha
+// o progresso em GET /jobs/{id} (cabeçalho Location).
+func (s *Server) createJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.createJob(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// createJob decodifica a requisição e cria o job. Em caso de erro, escreve a resposta de erro e retorna false.
+func (s *Server) createJob(w http.ResponseWriter, r *http.Request) (Job, bool) {
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Payload de requisição inválido: %v", err), http.StatusBadRequest)
+		return Job{}, false
+	}
+	if req.CallbackURL != "" {
+		if err := s.webhooks.validateCallbackURL(req.CallbackURL); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return Job{}, false
+		}
+	}
+	job, err := s.jobs.Create(req.PuzzleRequest, req.CallbackURL)
+	if err != nil {
+		status, msg := generationErrorStatus(err)
+		http.Error(w, msg, status)
+		return Job{}, false
+	}
+	return job, true
+}
+
+// getJobHandler é o manipulador de GET /jobs/{id}.
+func (s *Server) getJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.Get(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Job não encontrado.", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
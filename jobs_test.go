@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"puzzle_proxy_api/puzzlepb"
+)
+
+// Com MaxPending jobs não terminados, novos jobs são recusados com 429 (RESOURCE_EXHAUSTED no gRPC) até
+// que um deles termine.
+func TestJobManagerLimitsPendingJobs(t *testing.T) {
+	release := make(chan struct{})
+	finished := make(chan struct{}, 3)
+	s := &Server{store: NewMemoryStore()}
+	s.jobs = NewJobManager(JobsConfig{MaxConcurrent: 1, MaxPending: 2, Retention: time.Hour}, func(ctx context.Context, req PuzzleRequest) (*generatedPuzzle, error) {
+		<-release
+		return s.generate(ctx, req)
+	}, func(Job) { finished <- struct{}{} })
+	handler := s.routes()
+
+	post := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"gameType":"sudoku","difficulty":"easy"}`)))
+		return rec
+	}
+	for i := 0; i < 2; i++ {
+		if rec := post(); rec.Code != http.StatusAccepted {
+			t.Fatalf("job %d: status %d, quer 202", i+1, rec.Code)
+		}
+	}
+	if rec := post(); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("job além de MaxPending: status %d, quer 429", rec.Code)
+	}
+	g := &puzzleGRPCServer{server: s}
+	if _, err := g.CreateJob(context.Background(), &puzzlepb.PuzzleRequest{GameType: "sudoku", Difficulty: "easy"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("CreateJob além de MaxPending = %v, quer codes.ResourceExhausted", err)
+	}
+
+	release <- struct{}{}
+	<-finished
+	if rec := post(); rec.Code != http.StatusAccepted {
+		t.Errorf("job depois de um terminar: status %d, quer 202", rec.Code)
+	}
+	close(release)
+	for i := 0; i < 2; i++ {
+		<-finished
+	}
+}
+
+// Os jobs terminados são removidos depois de Retention mesmo sem novos jobs; os não terminados ficam.
+func TestJobManagerPrunesFinishedJobs(t *testing.T) {
+	release := make(chan struct{})
+	finished := make(chan struct{}, 1)
+	m := NewJobManager(JobsConfig{MaxConcurrent: 2, Retention: time.Hour}, func(ctx context.Context, req PuzzleRequest) (*generatedPuzzle, error) {
+		if req.Difficulty == "hard" {
+			<-release
+		}
+		return nil, &GeminiBlockedError{Reason: "SAFETY"}
+	}, func(job Job) {
+		if job.Request.Difficulty == "easy" {
+			finished <- struct{}{}
+		}
+	})
+	done, err := m.Create(PuzzleRequest{GameType: "crossword", Difficulty: "easy"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	running, err := m.Create(PuzzleRequest{GameType: "crossword", Difficulty: "hard"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(release)
+	<-finished
+
+	m.prune(time.Now().Add(30 * time.Minute))
+	if _, ok := m.Get(done.ID); !ok {
+		t.Error("job terminado removido antes de Retention")
+	}
+	m.prune(time.Now().Add(2 * time.Hour))
+	if _, ok := m.Get(done.ID); ok {
+		t.Error("job terminado não removido depois de Retention")
+	}
+	if _, ok := m.Get(running.ID); !ok {
+		t.Error("job em execução removido")
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import "strings"
+
+// languageAliases normaliza nomes de idioma comuns (em inglês e português) para códigos ISO 639-1.
+var languageAliases = map[string]string{
+	"english": "en", "inglês": "en", "ingles": "en",
+	"portuguese": "pt", "português": "pt", "portugues": "pt",
+	"spanish": "es", "español": "es", "espanol": "es", "espanhol": "es",
+	"german": "de", "deutsch": "de", "alemão": "de", "alemao": "de",
+	"french": "fr", "français": "fr", "francais": "fr", "francês": "fr", "frances": "fr",
+}
+
+// languageCode converte o idioma da requisição ("pt", "pt-BR", "Portuguese", ...) no código de duas letras
+// usado pelos recursos que dependem do idioma, como os alfabetos do criptograma e as listas de palavras.
+func languageCode(language string) string {
+	code := strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := languageAliases[code]; ok {
+		return alias
+	}
+	code, _, _ = strings.Cut(strings.ReplaceAll(code, "_", "-"), "-") // "pt-BR" -> "pt"
+	return code
+}
@@ -1,21 +1,34 @@
 package main
 
 import (
+	"context"
 	"crypto/sha256" // Para gerar hashes únicos para cache.
 	"encoding/hex"  // Para codificar bytes de hash em uma string hexadecimal.
 	"encoding/json" // Para codificação e decodificação JSON.
 	"fmt"
 	"log"      // Para mensagens de log.
 	"net/http" // Para criar o servidor HTTP e lidar com requisições.
-	"os"       // Para acessar variáveis de ambiente.
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv" // Biblioteca para carregar variáveis de ambiente de um arquivo .env.
 )
 
-// Server struct contém as dependências para o servidor HTTP, incluindo o banco de dados e o serviço Gemini.
+// Server struct contém as dependências para o servidor HTTP, incluindo o cache e o serviço Gemini.
 type Server struct {
-	dbService         *DBService         // Serviço para interações com o banco de dados (cache).
-	geminiPuzzleService *GeminiPuzzleService // Serviço para interagir com a API Gemini.
+	store                CacheStore           // Backend de cache (PostgreSQL, SQLite ou memória).
+	geminiPuzzleService  *GeminiPuzzleService // Serviço para interagir com a API Gemini.
+	adminToken           string               // Token exigido pelos endpoints /admin (vazio os desativa).
+	daily                DailyConfig          // Configuração do quebra-cabeça do dia.
+	dailyLocks           keyedMutex           // Evita gerar o mesmo quebra-cabeça do dia em paralelo.
+	idempotencyLocks     keyedMutex           // Serializa as requisições com a mesma Idempotency-Key.
+	idempotencyRetention time.Duration        // Tempo em que uma Idempotency-Key repete a primeira resposta.
+	cors                 CORSConfig           // Cabeçalhos CORS dos endpoints públicos.
+	httpCache            HTTPCacheConfig      // Cache-Control e compressão das respostas.
+	openAPIDoc           []byte               // Especificação OpenAPI servida em /openapi.json, gerada por routes.
+	openAPIETag          string               // ETag de openAPIDoc.
+	jobs                 *JobManager          // Gerações em segundo plano (POST /jobs e API gRPC).
+	webhooks             *webhookSender       // Entrega os callbacks dos jobs terminados.
 }
 
 func main() {
@@ -26,56 +39,112 @@ func main() {
 		log.Println("Nenhum arquivo .env encontrado, assumindo que as variáveis de ambiente estão definidas diretamente.")
 	}
 
-	// Recupera a string de conexão do banco de dados das variáveis de ambiente.
-	dbConnStr := os.Getenv("DATABASE_URL")
-	if dbConnStr == "" {
-		log.Fatal("Variável de ambiente DATABASE_URL não definida. Por favor, forneça sua string de conexão PostgreSQL.")
+	// Lê a configuração (backend de cache, credenciais e porta) das variáveis de ambiente.
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalf("Configuração inválida: %v", err)
 	}
 
-	// Recupera a chave da API Gemini das variáveis de ambiente.
-	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("Variável de ambiente GEMINI_API_KEY não definida. Por favor, forneça sua chave da API Gemini.")
+	// Inicializa o backend de cache selecionado por CACHE_BACKEND.
+	store, err := NewCacheStore(cfg)
+	if err != nil {
+		log.Fatalf("Falha ao inicializar o backend de cache %q: %v", cfg.CacheBackend, err)
 	}
+	defer store.Close() // Garante que o backend seja fechado quando a função principal sair.
 
-	// Inicializa o serviço de banco de dados.
-	dbService, err := NewDBService(dbConnStr)
+	// Carrega os templates de prompt (embutidos, com as substituições de PROMPTS_DIR).
+	prompts, err := LoadPrompts(cfg.PromptsDir)
 	if err != nil {
-		log.Fatalf("Falha ao inicializar o serviço de banco de dados: %v", err)
+		log.Fatalf("Falha ao carregar os templates de prompt: %v", err)
 	}
-	defer dbService.Close() // Garante que a conexão com o banco de dados seja fechada quando a função principal sair.
 
-	// Inicializa o serviço de quebra-cabeças Gemini com a chave da API.
-	geminiPuzzleService := NewGeminiPuzzleService(geminiAPIKey)
+	// Inicializa o serviço de quebra-cabeças Gemini com a configuração da API e os prompts.
+	geminiPuzzleService := NewGeminiPuzzleService(cfg.Gemini, prompts)
 
 	// Cria uma nova instância de servidor, injetando os serviços inicializados.
 	server := &Server{
-		dbService:         dbService,
-		geminiPuzzleService: geminiPuzzleService,
+		store:                store,
+		geminiPuzzleService:  geminiPuzzleService,
+		adminToken:           cfg.AdminToken,
+		daily:                cfg.Daily,
+		cors:                 cfg.CORS,
+		httpCache:            cfg.HTTPCache,
+		idempotencyRetention: cfg.IdempotencyRetention,
 	}
+	server.webhooks = newWebhookSender(cfg.Webhooks, store)
+	server.jobs = NewJobManager(cfg.Jobs, server.generate, server.webhooks.notify)
+	go server.jobs.runPruner()
 
-	// Registra o manipulador HTTP para o endpoint /generate-puzzle.
-	http.HandleFunc("/generate-puzzle", server.generatePuzzleHandler)
+	// Gera em segundo plano os quebra-cabeças do dia antes que os jogadores os peçam.
+	go server.runDailyScheduler()
 
-	// Determina a porta para escutar. Padrão para 8080 se não especificado nas variáveis de ambiente.
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// A API gRPC compartilha o cache e a geração com os endpoints HTTP, em uma porta própria.
+	if cfg.GRPCPort != "" {
+		go func() {
+			log.Printf("Servidor gRPC iniciando na porta %s...", cfg.GRPCPort)
+			log.Fatal(server.serveGRPC(cfg.GRPCPort))
+		}()
 	}
-	log.Printf("Servidor iniciando na porta %s...", port)
+
+	log.Printf("Servidor iniciando na porta %s (cache: %s)...", cfg.Port, cfg.CacheBackend)
 	// Inicia o servidor HTTP. log.Fatal fará com que o programa seja encerrado se o servidor falhar ao iniciar.
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	log.Fatal(http.ListenAndServe(":"+cfg.Port, server.routes()))
+}
+
+// routes registra os manipuladores HTTP da tabela apiRoutes e retorna o roteador resultante.
+// A especificação OpenAPI servida em /openapi.json é gerada a partir da mesma tabela.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	routes := s.apiRoutes()
+	doc, err := json.Marshal(buildOpenAPI(routes))
+	if err != nil {
+		log.Fatalf("Falha ao gerar a especificação OpenAPI: %v", err)
+	}
+	s.openAPIDoc, s.openAPIETag = doc, etagFor(doc)
+
+	configured := map[string]bool{}
+	for _, rt := range routes {
+		pattern := rt.Method + " " + rt.Path
+		handler := rt.Handler
+		if rt.Idempotent {
+			handler = s.withIdempotency(rt.Name, handler)
+		}
+		cacheControl, ok := s.httpCache.CacheControl[strings.ToLower(rt.Name)]
+		if !ok {
+			cacheControl = rt.CacheControl
+		}
+		configured[strings.ToLower(rt.Name)] = true
+		if cacheControl != "" {
+			handler = withCacheControl(cacheControl, handler)
+		}
+
+		if !rt.Admin {
+			mux.HandleFunc(pattern, handler)
+		} else if s.adminToken != "" {
+			// Os endpoints administrativos só existem quando ADMIN_TOKEN está configurado.
+			mux.Handle(pattern, s.requireAdmin(handler))
+		}
+	}
+	if s.adminToken == "" {
+		log.Println("ADMIN_TOKEN não definido; endpoints /admin desativados.")
+	}
+	for name := range s.httpCache.CacheControl {
+		if !configured[name] {
+			log.Fatalf("CACHE_CONTROL_%s não corresponde a nenhum endpoint (use o operationId de /openapi.json)", strings.ToUpper(name))
+		}
+	}
+
+	var handler http.Handler = mux
+	if s.httpCache.Compression {
+		handler = compressMiddleware(s.httpCache.CompressionMinSize, handler)
+	}
+	return corsMiddleware(s.cors, handler)
 }
 
 // generatePuzzleHandler é o manipulador HTTP para requisições de geração de quebra-cabeças.
 // Ele lida com a lógica de cache: verifica o cache, chama o Gemini se não encontrado e salva no cache.
 func (s *Server) generatePuzzleHandler(w http.ResponseWriter, r *http.Request) {
-	// Garante que apenas requisições POST sejam permitidas.
-	if r.Method != http.MethodPost {
-		http.Error(w, "Apenas requisições POST são permitidas para este endpoint.", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req PuzzleRequest
 	// Decodifica o corpo da requisição JSON para a struct PuzzleRequest.
 	err := json.NewDecoder(r.Body).Decode(&req)
@@ -84,52 +153,186 @@ func (s *Server) generatePuzzleHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Serializa a struct da requisição de volta para JSON para criar uma representação de bytes consistente para hashing.
-	// Isso garante que o hash seja o mesmo para requisições idênticas, independentemente da ordem de iteração do mapa, etc.
-	reqBytes, err := json.Marshal(req)
+	// Retorna a resposta em cache ou gera um novo quebra-cabeça com o Gemini.
+	puzzle, err := s.generate(r.Context(), req)
 	if err != nil {
-		log.Printf("Erro ao serializar a requisição para hashing: %v", err)
-		http.Error(w, "Erro interno do servidor: Falha ao processar a requisição.", http.StatusInternalServerError)
+		log.Printf("Erro ao gerar quebra-cabeça para a requisição %+v: %v", req, err)
+		status, msg := generationErrorStatus(err)
+		http.Error(w, msg, status)
 		return
 	}
 
-	// Gera um hash SHA256 dos bytes da requisição. Este hash serve como chave de cache.
-	hasher := sha256.New()
-	hasher.Write(reqBytes)
-	requestHash := hex.EncodeToString(hasher.Sum(nil)) // Converte o hash para uma string hexadecimal.
+	// Escreve o quebra-cabeça de volta para o cliente, com o ETag derivado da resposta armazenada.
+	writeCacheable(w, r, puzzle.etag(), puzzle.Data)
+}
+
+// UnsupportedGameTypeError indica um gameType que não está no registro de tipos de jogo.
+type UnsupportedGameTypeError struct {
+	GameType string
+}
+
+func (e *UnsupportedGameTypeError) Error() string {
+	return fmt.Sprintf("gameType não suportado %q. Disponíveis: %s", e.GameType, strings.Join(gameTypeNames(), ", "))
+}
 
-	// Tenta recuperar uma resposta em cache do banco de dados.
-	cachedResponse, err := s.dbService.GetCachedPuzzle(requestHash)
+// UnsupportedRequestError indica uma requisição que o tipo de jogo não atende (ver GameTypeSpec.CheckRequest).
+type UnsupportedRequestError struct {
+	GameType string
+	Err      error
+}
+
+func (e *UnsupportedRequestError) Error() string {
+	return fmt.Sprintf("requisição não suportada para %s: %v", e.GameType, e.Err)
+}
+
+func (e *UnsupportedRequestError) Unwrap() error {
+	return e.Err
+}
+
+// generatedPuzzle é um quebra-cabeça pronto para ser enviado ao cliente.
+type generatedPuzzle struct {
+	PuzzleID string        // ID público
+	Data     []byte        // JSON do quebra-cabeça no formato legado, já com puzzleId (e puzzleDate, nos diários)
+	Cached   bool          // Se veio do cache
+	Entry    *CachedPuzzle // Registro do cache, com os metadados da geração usados no envelope dos endpoints /v1
+}
+
+// newGeneratedPuzzle monta o quebra-cabeça de resposta a partir do registro do cache.
+func newGeneratedPuzzle(entry *CachedPuzzle, cached bool) *generatedPuzzle {
+	fields := map[string]interface{}{"puzzleId": entry.PuzzleID}
+	if entry.PuzzleDate != "" {
+		fields["puzzleDate"] = entry.PuzzleDate
+	}
+	return &generatedPuzzle{
+		PuzzleID: entry.PuzzleID,
+		Data:     withResponseFields(entry.ResponseData, fields),
+		Cached:   cached,
+		Entry:    entry,
+	}
+}
+
+// etag retorna o ETag do quebra-cabeça, derivado da resposta armazenada no cache.
+func (p *generatedPuzzle) etag() string {
+	return etagFor(p.Entry.ResponseData)
+}
+
+// generate é a lógica compartilhada por POST /generate-puzzle, pelos jobs e pela API gRPC: valida o
+// tipo de jogo, calcula a chave de cache e retorna o quebra-cabeça em cache ou gera um novo. ctx é o da
+// requisição (HTTP ou gRPC): se o cliente desistir, a chamada ao Gemini em andamento é cancelada.
+func (s *Server) generate(ctx context.Context, req PuzzleRequest) (*generatedPuzzle, error) {
+	if _, err := gameTypeFor(req); err != nil {
+		return nil, err
+	}
+
+	// Calcula a chave de cache a partir dos parâmetros da requisição e da versão do prompt.
+	entry, err := s.cacheEntry(req, "")
 	if err != nil {
-		log.Printf("Erro ao verificar o cache para o hash %s: %v", requestHash, err)
-		// Registra o erro, mas continua o processamento; uma falha na verificação do cache não deve bloquear a requisição.
+		return nil, fmt.Errorf("falha ao serializar a requisição para hashing: %w", err)
 	}
+	return s.getOrGeneratePuzzle(ctx, req, entry)
+}
 
-	// Se uma resposta em cache for encontrada, retorne-a imediatamente.
-	if cachedResponse != nil {
-		w.Header().Set("Content-Type", "application/json") // Define o tipo de conteúdo da resposta.
-		w.Write(cachedResponse)                            // Escreve os dados JSON em cache na resposta.
-		log.Printf("Retornada resposta em cache para o hash: %s", requestHash)
-		return // Encerra o processamento da requisição aqui.
+// puzzleCacheKey reúne tudo o que identifica um quebra-cabeça no cache: os parâmetros da requisição,
+// a data (apenas nos quebra-cabeças do dia), a versão do prompt e o modelo preferido para a geração.
+// Incluir a versão e o modelo faz com que uma mudança no texto do prompt ou na configuração dos modelos
+// gere novos quebra-cabeças em vez de servir os antigos. Os quebra-cabeças do dia não incluem nenhum dos
+// dois: todos os jogadores devem receber o mesmo quebra-cabeça durante a data, mesmo que um template ou
+// a cadeia de modelos mude no meio do dia. Os campos vazios são omitidos, então requisições sem data nem
+// prompt (ex: sudoku) mantêm o hash de antes.
+type puzzleCacheKey struct {
+	Date string `json:"date,omitempty"` // Data no formato AAAA-MM-DD
+	PuzzleRequest
+	PromptVersion string `json:"promptVersion,omitempty"` // Versão do template de prompt
+	Model         string `json:"model,omitempty"`         // Primeiro modelo da cadeia configurada
+}
+
+// cacheEntry monta o registro de cache (hash, parâmetros, data, versão do prompt e do schema) de uma requisição.
+// O modelo do registro é preenchido depois da geração, com o modelo da cadeia que de fato gerou o quebra-cabeça.
+// date é vazio para quebra-cabeças que não são do dia; nos do dia, a versão do prompt é registrada mas
+// não entra na chave (ver puzzleCacheKey).
+func (s *Server) cacheEntry(req PuzzleRequest, date string) (*CachedPuzzle, error) {
+	key := puzzleCacheKey{Date: date, PuzzleRequest: req}
+	var promptVersion, schemaVersion string
+	if gt, ok := lookupGameType(req.GameType); ok {
+		schemaVersion = gt.schemaVersion
+		if !gt.isLocal() {
+			promptVersion = s.geminiPuzzleService.PromptVersion(gt)
+			if date == "" {
+				key.PromptVersion = promptVersion
+				key.Model = s.geminiPuzzleService.Models(req)[0]
+			}
+		}
+	}
+	reqBytes, requestHash, err := hashRequest(key)
+	if err != nil {
+		return nil, err
 	}
+	return &CachedPuzzle{
+		RequestHash:   requestHash,
+		PuzzleID:      puzzleIDForHash(requestHash),
+		RequestParams: reqBytes,
+		PuzzleDate:    date,
+		PromptVersion: promptVersion,
+		SchemaVersion: schemaVersion,
+	}, nil
+}
 
-	// Se nenhuma resposta em cache, chama a API Gemini para gerar um novo quebra-cabeça.
-	geminiResponse, err := s.geminiPuzzleService.GeneratePuzzle(req)
+// hashRequest serializa v para JSON e calcula seu hash SHA256, usado como chave de cache.
+// Serializar a struct (em vez de usar o corpo recebido) cria uma representação de bytes consistente,
+// garantindo que o hash seja o mesmo para requisições idênticas, independentemente da formatação do cliente.
+func hashRequest(v interface{}) ([]byte, string, error) {
+	reqBytes, err := json.Marshal(v)
 	if err != nil {
-		log.Printf("Erro ao gerar quebra-cabeça da API Gemini para a requisição %+v: %v", req, err)
-		http.Error(w, fmt.Sprintf("Falha ao gerar quebra-cabeça: %v", err), http.StatusInternalServerError)
-		return
+		return nil, "", err
+	}
+	sum := sha256.Sum256(reqBytes)
+	return reqBytes, hex.EncodeToString(sum[:]), nil // Converte o hash para uma string hexadecimal.
+}
+
+// getOrGeneratePuzzle implementa a lógica de cache compartilhada pelos endpoints: retorna o registro
+// em cache para entry.RequestHash ou, em caso de cache miss, chama o Gemini e salva o resultado em entry.
+func (s *Server) getOrGeneratePuzzle(ctx context.Context, req PuzzleRequest, entry *CachedPuzzle) (*generatedPuzzle, error) {
+	// Tenta recuperar o registro em cache do banco de dados.
+	cached, err := s.store.GetCachedPuzzleEntry(entry.RequestHash)
+	if err != nil {
+		log.Printf("Erro ao verificar o cache para o hash %s: %v", entry.RequestHash, err)
+		// Registra o erro, mas continua o processamento; uma falha na verificação do cache não deve bloquear a requisição.
 	}
 
-	// Após obter uma resposta com sucesso do Gemini, salve-a no cache.
-	err = s.dbService.SaveCachedPuzzle(requestHash, reqBytes, geminiResponse)
+	// Se um registro em cache for encontrado, retorne-o imediatamente.
+	if cached != nil {
+		log.Printf("Retornada resposta em cache para o hash: %s", entry.RequestHash)
+		return newGeneratedPuzzle(cached, true), nil
+	}
+
+	// Se nenhuma resposta em cache, gera um novo quebra-cabeça.
+	geminiResponse, model, err := s.generatePuzzle(ctx, req)
 	if err != nil {
-		log.Printf("Erro ao salvar quebra-cabeça no cache para o hash %s: %v", requestHash, err)
+		return nil, err
+	}
+
+	// Após gerar o quebra-cabeça com sucesso, salve-o no cache.
+	entry.ResponseData = geminiResponse
+	entry.Model = model
+	entry.CreatedAt = time.Now().UTC()
+	if err := s.store.SaveCachedPuzzle(entry); err != nil {
+		log.Printf("Erro ao salvar quebra-cabeça no cache para o hash %s: %v", entry.RequestHash, err)
 		// Registra o erro, mas continua a retornar a resposta; uma falha ao salvar no cache não deve bloquear o usuário.
 	}
+	log.Printf("Nova resposta gerada e salva no cache para o hash: %s", entry.RequestHash)
+	return newGeneratedPuzzle(entry, false), nil
+}
 
-	// Define o tipo de conteúdo e escreve a resposta do Gemini de volta para o cliente.
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(geminiResponse)
-	log.Printf("Nova resposta gerada e salva no cache para o hash: %s", requestHash)
+// generatePuzzle gera um novo quebra-cabeça com o tipo de jogo registrado e retorna também o modelo Gemini
+// usado. Tipos com geração local (como o sudoku) não chamam a API Gemini e retornam o modelo vazio.
+func (s *Server) generatePuzzle(ctx context.Context, req PuzzleRequest) ([]byte, string, error) {
+	gt, ok := lookupGameType(req.GameType)
+	if !ok {
+		return nil, "", fmt.Errorf("gameType não suportado: %q", req.GameType)
+	}
+	if gt.isLocal() {
+		puzzle, err := gt.generateLocal(ctx, req)
+		return puzzle, "", err
+	}
+	return s.geminiPuzzleService.GeneratePuzzle(ctx, gt, req)
 }
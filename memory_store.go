@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryEntry é um registro do cache em memória, equivalente a uma linha de cached_puzzles.
+type memoryEntry struct {
+	puzzle  CachedPuzzle  // Registro completo, como seria lido do banco de dados
+	request PuzzleRequest // RequestParams decodificado, usado pelos filtros
+}
+
+// MemoryStore é um CacheStore mantido apenas em memória. Os dados são perdidos quando o processo termina,
+// o que o torna adequado para desenvolvimento local e testes.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	entries     map[string]memoryEntry        // Indexado pelo hash da requisição
+	idempotency map[string]IdempotentResponse // Indexado por operation e Idempotency-Key (ver idempotencyMapKey)
+	deliveries  []WebhookDelivery             // Tentativas de entrega de webhooks, na ordem em que foram registradas
+}
+
+// NewMemoryStore cria um MemoryStore vazio.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), idempotency: make(map[string]IdempotentResponse)}
+}
+
+// SaveCachedPuzzle insere ou substitui o registro do hash. Os dados são copiados para que
+// alterações posteriores do chamador não afetem o cache.
+func (s *MemoryStore) SaveCachedPuzzle(puzzle *CachedPuzzle) error {
+	var req PuzzleRequest
+	_ = json.Unmarshal(puzzle.RequestParams, &req) // Parâmetros inválidos apenas não casam com os filtros.
+
+	stored := *puzzle
+	stored.PuzzleID = puzzleIDForHash(puzzle.RequestHash)
+	stored.RequestParams = append(json.RawMessage(nil), puzzle.RequestParams...)
+	stored.ResponseData = append(json.RawMessage(nil), puzzle.ResponseData...)
+	stored.CreatedAt = createdAtOrNow(puzzle.CreatedAt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[puzzle.RequestHash] = memoryEntry{puzzle: stored, request: req}
+	return nil
+}
+
+// GetCachedPuzzleByID retorna o registro com o ID público informado, ou nil se não houver registro.
+// Como o ID é um prefixo do hash, a busca percorre os registros sem precisar de um índice separado.
+func (s *MemoryStore) GetCachedPuzzleByID(puzzleID string) (*CachedPuzzle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, entry := range s.entries {
+		if entry.puzzle.PuzzleID == puzzleID {
+			puzzle := entry.copy(true)
+			return &puzzle, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetCachedPuzzleEntry retorna uma cópia do registro completo do hash, ou nil se não houver registro.
+func (s *MemoryStore) GetCachedPuzzleEntry(requestHash string) (*CachedPuzzle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[requestHash]
+	if !ok {
+		return nil, nil
+	}
+	puzzle := entry.copy(true)
+	return &puzzle, nil
+}
+
+// ListCachedPuzzles lista os registros que atendem ao filtro, do mais recente para o mais antigo.
+func (s *MemoryStore) ListCachedPuzzles(filter CacheFilter) ([]CachedPuzzle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := []CachedPuzzle{}
+	for _, entry := range s.entries {
+		if entry.matches(filter) {
+			entries = append(entries, entry.copy(false))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].RequestHash < entries[j].RequestHash
+	})
+
+	if filter.Offset >= len(entries) {
+		return []CachedPuzzle{}, nil
+	}
+	entries = entries[filter.Offset:]
+	if len(entries) > filter.limit() {
+		entries = entries[:filter.limit()]
+	}
+	return entries, nil
+}
+
+// DeleteCachedPuzzle remove o registro do hash e informa se ele existia.
+func (s *MemoryStore) DeleteCachedPuzzle(requestHash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.entries[requestHash]
+	delete(s.entries, requestHash)
+	return ok, nil
+}
+
+// PurgeCachedPuzzles remove todos os registros que atendem ao filtro.
+func (s *MemoryStore) PurgeCachedPuzzles(filter CacheFilter) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for hash, entry := range s.entries {
+		if entry.matches(filter) {
+			delete(s.entries, hash)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// GetIdempotentResponse retorna uma cópia da resposta guardada para a Idempotency-Key, ou nil se não houver.
+func (s *MemoryStore) GetIdempotentResponse(operation, key string) (*IdempotentResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	resp, ok := s.idempotency[idempotencyMapKey(operation, key)]
+	if !ok {
+		return nil, nil
+	}
+	resp.Body = append([]byte(nil), resp.Body...)
+	return &resp, nil
+}
+
+// SaveIdempotentResponse guarda uma cópia da resposta, substituindo a anterior com a mesma chave.
+func (s *MemoryStore) SaveIdempotentResponse(resp *IdempotentResponse) error {
+	stored := *resp
+	stored.Body = append([]byte(nil), resp.Body...)
+	stored.CreatedAt = createdAtOrNow(resp.CreatedAt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.idempotency[idempotencyMapKey(resp.Operation, resp.Key)] = stored
+	return nil
+}
+
+// PurgeIdempotentResponses remove as respostas guardadas antes de before.
+func (s *MemoryStore) PurgeIdempotentResponses(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for k, resp := range s.idempotency {
+		if resp.CreatedAt.Before(before) {
+			delete(s.idempotency, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// idempotencyMapKey combina o endpoint e a Idempotency-Key em uma chave do mapa.
+func idempotencyMapKey(operation, key string) string {
+	return operation + "\x00" + key
+}
+
+// SaveWebhookDelivery registra uma tentativa de entrega de webhook.
+func (s *MemoryStore) SaveWebhookDelivery(delivery *WebhookDelivery) error {
+	stored := *delivery
+	stored.CreatedAt = createdAtOrNow(delivery.CreatedAt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, stored)
+	return nil
+}
+
+// ListWebhookDeliveries lista as tentativas de entrega de webhooks, da mais recente para a mais antiga.
+func (s *MemoryStore) ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	deliveries := []WebhookDelivery{}
+	skipped := 0
+	for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < filter.limit(); i-- {
+		if d := s.deliveries[i]; filter.JobID == "" || d.JobID == filter.JobID {
+			if skipped < filter.Offset {
+				skipped++
+				continue
+			}
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// Close não tem recursos a liberar no backend em memória.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// copy retorna uma cópia independente do registro; withResponse controla se ResponseData é incluído,
+// como na listagem do DBService, que não lê response_data.
+func (e memoryEntry) copy(withResponse bool) CachedPuzzle {
+	puzzle := e.puzzle
+	puzzle.RequestParams = append(json.RawMessage(nil), e.puzzle.RequestParams...)
+	puzzle.ResponseData = nil
+	if withResponse {
+		puzzle.ResponseData = append(json.RawMessage(nil), e.puzzle.ResponseData...)
+	}
+	return puzzle
+}
+
+// matches aplica o filtro com a mesma semântica das consultas SQL do DBService.
+func (e memoryEntry) matches(filter CacheFilter) bool {
+	if filter.GameType != "" && !strings.EqualFold(e.request.GameType, filter.GameType) {
+		return false
+	}
+	if filter.Difficulty != "" && !strings.EqualFold(e.request.Difficulty, filter.Difficulty) {
+		return false
+	}
+	if filter.Language != "" && !strings.EqualFold(e.request.Language, filter.Language) {
+		return false
+	}
+	if filter.Topic != "" {
+		found := false
+		for _, topic := range e.request.Topics {
+			if strings.EqualFold(topic, filter.Topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	createdAt := e.puzzle.CreatedAt
+	if !filter.CreatedFrom.IsZero() && createdAt.Before(filter.CreatedFrom) {
+		return false
+	}
+	if !filter.CreatedTo.IsZero() && !createdAt.Before(filter.CreatedTo) {
+		return false
+	}
+	return true
+}
@@ -1,51 +1,177 @@
 package main
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // PuzzleRequest representa o payload da requisição recebida do aplicativo Dart.
 // Contém os parâmetros para gerar um quebra-cabeça.
 type PuzzleRequest struct {
-	GameType   string   `json:"gameType"`   // Ex: "crossword", "wordsearch"
-	Difficulty string   `json:"difficulty"` // Ex: "easy", "medium", "hard"
-	Topics     []string `json:"topics"`     // Lista de tópicos para o quebra-cabeça
-	Language   string   `json:"language"`   // Idioma do quebra-cabeça
+	GameType   string   `json:"gameType" description:"One of the registered game types" openapi:"gameType"` // Um dos tipos de jogo registrados (ver gametypes.go), ex: "crossword", "wordsearch", "sudoku"
+	Difficulty string   `json:"difficulty" description:"easy, medium or hard"`                              // Ex: "easy", "medium", "hard"
+	Topics     []string `json:"topics" description:"Topics for the puzzle; may be empty"`                   // Lista de tópicos para o quebra-cabeça
+	Language   string   `json:"language" description:"Puzzle language, e.g. en or pt"`                      // Idioma do quebra-cabeça
 }
 
 // CrosswordWord representa uma única palavra dentro de um quebra-cabeça de palavras cruzadas.
 type CrosswordWord struct {
-	Word      string `json:"word"`      // A palavra real
-	Clue      string `json:"clue"`      // A dica para a palavra
-	StartRow  int    `json:"startRow"`  // Linha inicial (base 0) na grade
-	StartCol  int    `json:"startCol"`  // Coluna inicial (base 0) na grade
-	Direction string `json:"direction"` // "across" (horizontal) ou "down" (vertical)
+	Word      string `json:"word" schema:"required" description:"The answer, uppercase letters only"`       // A palavra real
+	Clue      string `json:"clue" schema:"required"`                                                        // A dica para a palavra
+	StartRow  int    `json:"startRow" schema:"required" description:"0-indexed row of the first letter"`    // Linha inicial (base 0) na grade
+	StartCol  int    `json:"startCol" schema:"required" description:"0-indexed column of the first letter"` // Coluna inicial (base 0) na grade
+	Direction string `json:"direction" schema:"required" enum:"across,down"`                                // "across" (horizontal) ou "down" (vertical)
 }
 
 // CrosswordData encapsula todos os dados específicos de um quebra-cabeça de palavras cruzadas.
 type CrosswordData struct {
 	GridSize struct { // Dimensões da grade de palavras cruzadas
-		Rows int `json:"rows"`
-		Cols int `json:"cols"`
-	} `json:"gridSize"`
-	Words []CrosswordWord `json:"words"` // Lista de palavras nas palavras cruzadas
+		Rows int `json:"rows" schema:"required"`
+		Cols int `json:"cols" schema:"required"`
+	} `json:"gridSize" schema:"required"`
+	Words []CrosswordWord `json:"words" schema:"required"` // Lista de palavras nas palavras cruzadas
 }
 
 // WordSearchData encapsula todos os dados específicos de um caça-palavras.
 type WordSearchData struct {
 	GridSize struct { // Dimensões da grade do caça-palavras
-		Rows int `json:"rows"`
-		Cols int `json:"cols"`
-	} `json:"gridSize"`
-	WordsToFind []string `json:"wordsToFind"` // Lista de palavras a serem encontradas no caça-palavras
+		Rows int `json:"rows" schema:"required"`
+		Cols int `json:"cols" schema:"required"`
+	} `json:"gridSize" schema:"required"`
+	WordsToFind []string `json:"wordsToFind" schema:"required" description:"Uppercase words without spaces"` // Lista de palavras a serem encontradas no caça-palavras
+}
+
+// SudokuData encapsula todos os dados de um sudoku. Diferente dos outros tipos, é gerado pelo próprio proxy.
+type SudokuData struct {
+	Puzzle     [][]int  `json:"puzzle"`     // Grade 9x9 com as pistas; 0 indica uma célula vazia
+	Solution   [][]int  `json:"solution"`   // Grade 9x9 resolvida (a solução é única)
+	Givens     int      `json:"givens"`     // Quantidade de pistas na grade
+	Techniques []string `json:"techniques"` // Técnicas de resolução necessárias, da mais simples para a mais avançada
+}
+
+// QuizQuestion representa uma pergunta de múltipla escolha do quiz.
+type QuizQuestion struct {
+	Question     string   `json:"question" schema:"required"`                                                            // Enunciado da pergunta
+	Options      []string `json:"options" schema:"required" description:"Distinct answer options"`                       // Opções de resposta, todas distintas
+	CorrectIndex int      `json:"correctIndex" schema:"required" description:"0-indexed position of the correct option"` // Índice (base 0) da única opção correta
+	Explanation  string   `json:"explanation" schema:"required"`                                                         // Explicação da resposta correta
+}
+
+// QuizData encapsula todos os dados específicos de um quiz de perguntas e respostas.
+type QuizData struct {
+	Questions []QuizQuestion `json:"questions" schema:"required"` // Lista de perguntas do quiz
+}
+
+// ScrambleWord representa uma palavra do embaralhador. Word e Hint vêm do Gemini; Scrambled é gerado pelo proxy.
+type ScrambleWord struct {
+	Word      string `json:"word" schema:"required" description:"The answer, spelled correctly (not scrambled)"` // A palavra original (resposta)
+	Hint      string `json:"hint" schema:"required" description:"Short clue that does not contain the word"`     // Dica para adivinhar a palavra
+	Scrambled string `json:"scrambled" schema:"-"`                                                               // Letras embaralhadas exibidas ao jogador
+}
+
+// WordScrambleData encapsula todos os dados específicos de um embaralhador de palavras.
+type WordScrambleData struct {
+	Words []ScrambleWord `json:"words" schema:"required"` // Lista de palavras a desembaralhar
+}
+
+// CryptogramData encapsula todos os dados de um criptograma. Quote e Author vêm do Gemini;
+// os demais campos são gerados pelo proxy ao aplicar a cifra de substituição.
+type CryptogramData struct {
+	Quote        string            `json:"quote" schema:"required" description:"Plain text with normal spelling and punctuation"` // Texto original, com acentos e pontuação
+	Author       string            `json:"author" schema:"required" description:"Author's name, or empty if unknown"`             // Autor da citação (vazio se desconhecido)
+	Alphabet     string            `json:"alphabet" schema:"-"`                                                                   // Letras do alfabeto cifrado no idioma do quebra-cabeça
+	Ciphertext   string            `json:"ciphertext" schema:"-"`                                                                 // Texto cifrado exibido ao jogador
+	Solution     string            `json:"solution" schema:"-"`                                                                   // Texto normalizado (maiúsculas, acentos removidos) que corresponde à cifra letra a letra
+	GivenLetters map[string]string `json:"givenLetters,omitempty" schema:"-"`                                                     // Letras reveladas de início (letra cifrada -> letra original)
+}
+
+// WordLadderData encapsula todos os dados de uma escada de palavras. CandidateWords vem do Gemini e é
+// descartado depois que o proxy calcula a escada com a lista de palavras do idioma.
+type WordLadderData struct {
+	CandidateWords []string `json:"candidateWords,omitempty" schema:"required" description:"Topic words with 3 to 5 letters"` // Palavras dos tópicos sugeridas pelo Gemini (removidas na resposta final)
+	StartWord      string   `json:"startWord" schema:"-"`                                                                     // Palavra inicial
+	EndWord        string   `json:"endWord" schema:"-"`                                                                       // Palavra final
+	Ladder         []string `json:"ladder" schema:"-"`                                                                        // Caminho mais curto, da palavra inicial à final (inclusive)
+	Steps          int      `json:"steps" schema:"-"`                                                                         // Quantidade de trocas de letra (len(Ladder) - 1)
+}
+
+// ConnectionsGroup representa um grupo temático do quebra-cabeça de conexões.
+type ConnectionsGroup struct {
+	Label string   `json:"label" schema:"required" description:"Short description of the theme"`                    // Tema que liga as palavras do grupo (a resposta do grupo)
+	Level int      `json:"level" schema:"required" description:"1 (most obvious) to 4 (trickiest), each used once"` // Dificuldade do grupo, de 1 (mais fácil) a 4 (mais difícil)
+	Words []string `json:"words" schema:"required" description:"Exactly 4 uppercase words"`                         // As 4 palavras do grupo
+}
+
+// ConnectionsData encapsula todos os dados de um quebra-cabeça de conexões. Groups vem do Gemini;
+// Words é gerado pelo proxy.
+type ConnectionsData struct {
+	Groups []ConnectionsGroup `json:"groups" schema:"required"`   // Os 4 grupos, em ordem crescente de dificuldade
+	Words  []string           `json:"words,omitempty" schema:"-"` // As 16 palavras embaralhadas, na ordem exibida ao jogador
 }
 
 // GeminiPuzzleResponse representa a resposta estruturada esperada da API Gemini,
 // que será retornada ao aplicativo Dart.
+//
+// Os dados específicos do jogo ficam em Data, com o modelo registrado para o tipo de jogo (ex: *CrosswordData),
+// e são serializados no campo JSON do tipo (ex: "crosswordData"). Assim, novos tipos de jogo não precisam
+// de um campo próprio nesta struct.
 type GeminiPuzzleResponse struct {
-	GameType       string          `json:"gameType"`              // Tipo de jogo (crossword ou wordsearch)
-	Difficulty     string          `json:"difficulty"`            // Nível de dificuldade
-	Topics         []string        `json:"topics"`                // Tópicos usados para o quebra-cabeça
-	CrosswordData  *CrosswordData  `json:"crosswordData,omitempty"`  // Dados de palavras cruzadas (se o gameType for crossword)
-	WordSearchData *WordSearchData `json:"wordSearchData,omitempty"` // Dados de caça-palavras (se o gameType for wordsearch)
+	PuzzleID   string      `json:"puzzleId,omitempty" schema:"-"`                        // ID público e estável, adicionado pelo proxy (não gerado pelo Gemini)
+	PuzzleDate string      `json:"puzzleDate,omitempty" schema:"-"`                      // Data (AAAA-MM-DD), apenas no quebra-cabeça do dia (GET /daily)
+	GameType   string      `json:"gameType" schema:"required"`                           // Tipo de jogo registrado (crossword, wordsearch, sudoku, ...)
+	Difficulty string      `json:"difficulty" schema:"required" enum:"easy,medium,hard"` // Nível de dificuldade
+	Topics     []string    `json:"topics" schema:"required"`                             // Tópicos usados para o quebra-cabeça
+	Data       interface{} `json:"-"`                                                    // Dados do jogo, serializados no campo DataField do tipo de jogo
+}
+
+// geminiPuzzleResponseFields contém os campos comuns de GeminiPuzzleResponse, sem os métodos de (de)serialização.
+type geminiPuzzleResponseFields GeminiPuzzleResponse
+
+// MarshalJSON serializa os campos comuns e os dados do jogo no campo registrado para o tipo de jogo.
+func (r GeminiPuzzleResponse) MarshalJSON() ([]byte, error) {
+	out, err := json.Marshal(geminiPuzzleResponseFields(r))
+	if err != nil || r.Data == nil {
+		return out, err
+	}
+	gt, ok := lookupGameType(r.GameType)
+	if !ok {
+		return nil, fmt.Errorf("tipo de jogo não registrado: %q", r.GameType)
+	}
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return nil, err
+	}
+	field, _ := json.Marshal(gt.dataField)
+	out = append(out[:len(out)-1], ',') // Remove o "}" final para acrescentar o campo de dados.
+	out = append(append(append(out, field...), ':'), data...)
+	return append(out, '}'), nil
+}
+
+// UnmarshalJSON decodifica os campos comuns e, se o tipo de jogo estiver registrado, o campo de dados
+// correspondente no modelo do tipo. Campos de dados de outros tipos de jogo são ignorados.
+func (r *GeminiPuzzleResponse) UnmarshalJSON(b []byte) error {
+	var fields geminiPuzzleResponseFields
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	*r = GeminiPuzzleResponse(fields)
+
+	gt, ok := lookupGameType(r.GameType)
+	if !ok {
+		return nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if value, ok := raw[gt.dataField]; ok && string(value) != "null" {
+		data := gt.newData()
+		if err := json.Unmarshal(value, data); err != nil {
+			return fmt.Errorf("%s: %w", gt.dataField, err)
+		}
+		r.Data = data
+	}
+	return nil
 }
 
 // GeminiContentPart representa uma parte do conteúdo dentro de uma requisição/resposta da API Gemini.
@@ -56,17 +182,18 @@ type GeminiContentPart struct {
 
 // GeminiContent representa o bloco de conteúdo em uma requisição/resposta da API Gemini.
 type GeminiContent struct {
+	Role  string              `json:"role,omitempty"` // Autor do turno na conversa: "user" ou "model"
 	Parts []GeminiContentPart `json:"parts"`
 }
 
 // GeminiGenerationConfig define a configuração para o processo de geração da API Gemini.
 // Inclui o formato da resposta (schema) e parâmetros de geração.
 type GeminiGenerationConfig struct {
-	ResponseMimeType string          `json:"responseMimeType"`   // Tipo MIME esperado da resposta (ex: "application/json")
-	ResponseSchema   json.RawMessage `json:"responseSchema"`     // Schema JSON para a estrutura de saída desejada
-	Temperature      float64         `json:"temperature"`        // Controla a aleatoriedade na geração
-	TopP             float64         `json:"topP"`               // Controla a diversidade via amostragem de núcleo
-	TopK             int             `json:"topK"`               // Controla a diversidade via amostragem top-k
+	ResponseMimeType string        `json:"responseMimeType"` // Tipo MIME esperado da resposta (ex: "application/json")
+	ResponseSchema   *GeminiSchema `json:"responseSchema"`   // Schema JSON para a estrutura de saída desejada
+	Temperature      float64       `json:"temperature"`      // Controla a aleatoriedade na geração
+	TopP             float64       `json:"topP"`             // Controla a diversidade via amostragem de núcleo
+	TopK             int           `json:"topK"`             // Controla a diversidade via amostragem top-k
 }
 
 // GeminiRequest representa o payload completo enviado à API Gemini.
@@ -75,12 +202,28 @@ type GeminiRequest struct {
 	GenerationConfig GeminiGenerationConfig `json:"generationConfig"` // Configuração para geração
 }
 
+// GeminiSafetyRating é a avaliação de segurança de uma categoria de conteúdo.
+type GeminiSafetyRating struct {
+	Category    string `json:"category"`          // Ex: "HARM_CATEGORY_HARASSMENT"
+	Probability string `json:"probability"`       // Ex: "NEGLIGIBLE", "HIGH"
+	Blocked     bool   `json:"blocked,omitempty"` // Se esta categoria causou o bloqueio
+}
+
 // GeminiCandidate representa uma resposta candidata gerada pela API Gemini.
 type GeminiCandidate struct {
-	Content GeminiContent `json:"content"` // O conteúdo gerado
+	Content       GeminiContent        `json:"content"`                 // O conteúdo gerado
+	FinishReason  string               `json:"finishReason,omitempty"`  // Motivo do fim da geração (ex: "STOP", "MAX_TOKENS", "SAFETY")
+	SafetyRatings []GeminiSafetyRating `json:"safetyRatings,omitempty"` // Avaliações de segurança do conteúdo gerado
+}
+
+// GeminiPromptFeedback descreve a avaliação do prompt; BlockReason é preenchido quando o prompt foi recusado.
+type GeminiPromptFeedback struct {
+	BlockReason   string               `json:"blockReason,omitempty"`   // Ex: "SAFETY", "BLOCKLIST", "OTHER"
+	SafetyRatings []GeminiSafetyRating `json:"safetyRatings,omitempty"` // Avaliações de segurança do prompt
 }
 
 // GeminiAPIResponse representa a resposta completa recebida da API Gemini.
 type GeminiAPIResponse struct {
-	Candidates []GeminiCandidate `json:"candidates"` // Lista de candidatos gerados
+	Candidates     []GeminiCandidate     `json:"candidates"`               // Lista de candidatos gerados
+	PromptFeedback *GeminiPromptFeedback `json:"promptFeedback,omitempty"` // Avaliação do prompt
 }
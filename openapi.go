@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openAPIDocument é o subconjunto do documento OpenAPI 3 gerado para a API do proxy.
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"` // "path", "query" ou "header"
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// openAPISchema é o subconjunto do Schema Object usado pelos modelos do proxy.
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	MaxLength            int                       `json:"maxLength,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+	Discriminator        *openAPIDiscriminator     `json:"discriminator,omitempty"`
+}
+
+type openAPIDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// buildOpenAPI gera o documento OpenAPI a partir da tabela de rotas (a mesma usada para registrar os
+// manipuladores em routes) e dos tipos Go das requisições e respostas, então a especificação sempre
+// descreve exatamente os endpoints servidos.
+func buildOpenAPI(routes []apiRoute) *openAPIDocument {
+	g := &openAPIGenerator{schemas: map[string]*openAPISchema{}}
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Puzzle Proxy API",
+			Version:     "1.0.0",
+			Description: "Gera quebra-cabeças com a API Gemini (ou localmente) e os mantém em cache. Erros são retornados como text/plain. Métodos não documentados em um caminho retornam 405 com o cabeçalho Allow.",
+		},
+		Paths: map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas:         g.schemas,
+			SecuritySchemes: map[string]*openAPISecurityScheme{"adminToken": {Type: "http", Scheme: "bearer"}},
+		},
+	}
+
+	for _, rt := range routes {
+		op := &openAPIOperation{
+			OperationID: rt.Name,
+			Summary:     rt.Summary,
+			Tags:        []string{rt.Tag},
+			Responses:   map[string]*openAPIResponse{},
+		}
+		for _, p := range rt.Params {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.In == "path" || p.Required,
+				Schema:      &openAPISchema{Type: "string"},
+			})
+		}
+		if rt.Idempotent {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:        "Idempotency-Key",
+				In:          "header",
+				Description: "Chave única da operação; repetições com a mesma chave e o mesmo corpo recebem a primeira resposta",
+				Schema:      &openAPISchema{Type: "string", MaxLength: maxIdempotencyKeyLength},
+			})
+		}
+		if rt.Request != nil {
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: g.schemaFor(rt.Request)}},
+			}
+		}
+
+		status := rt.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &openAPIResponse{Description: http.StatusText(status)}
+		if rt.Response != nil {
+			success.Content = map[string]openAPIMediaType{"application/json": {Schema: g.schemaFor(rt.Response)}}
+		}
+		op.Responses[strconv.Itoa(status)] = success
+
+		errs := rt.Errors
+		if rt.Admin {
+			errs = append([]apiError{{http.StatusUnauthorized, "ADMIN_TOKEN ausente ou inválido"}}, errs...)
+			op.Security = []map[string][]string{{"adminToken": {}}}
+		}
+		for _, e := range errs {
+			if resp, ok := op.Responses[strconv.Itoa(e.Status)]; ok {
+				resp.Description += "; " + e.Description // Mais de um motivo para o mesmo status.
+				continue
+			}
+			resp := &openAPIResponse{Description: e.Description}
+			if e.Status != http.StatusNotModified {
+				resp.Content = map[string]openAPIMediaType{"text/plain": {Schema: &openAPISchema{Type: "string"}}}
+			}
+			op.Responses[strconv.Itoa(e.Status)] = resp
+		}
+
+		if doc.Paths[rt.Path] == nil {
+			doc.Paths[rt.Path] = map[string]*openAPIOperation{}
+		}
+		doc.Paths[rt.Path][strings.ToLower(rt.Method)] = op
+	}
+
+	return doc
+}
+
+// openAPIGenerator converte tipos Go em schemas OpenAPI. Structs nomeadas viram componentes
+// referenciados por $ref; os nomes seguem os tipos Go, com a inicial maiúscula.
+type openAPIGenerator struct {
+	schemas map[string]*openAPISchema
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	rawMessageType      = reflect.TypeOf(json.RawMessage(nil))
+	puzzleResponseType  = reflect.TypeOf(GeminiPuzzleResponse{})
+	puzzleComponentName = "Puzzle"
+)
+
+// schemaFor retorna o schema de t, registrando os componentes necessários. Os campos seguem as tags json
+// (campos sem omitempty são obrigatórios) e usam as tags description e enum dos modelos; a tag
+// openapi:"puzzle" documenta um json.RawMessage como o componente Puzzle, e a tag openapi:"gameType"
+// limita um campo aos tipos de jogo registrados (inclusive quando a struct está embutida, como em JobRequest).
+func (g *openAPIGenerator) schemaFor(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &openAPISchema{Description: "JSON arbitrário"}
+	case puzzleResponseType:
+		return g.puzzleSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:] // Tipos não exportados (ex: adminPuzzleList) também viram componentes.
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = &openAPISchema{} // Reserva o nome antes de percorrer os campos (tipos recursivos).
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	default:
+		return &openAPISchema{} // interface{}: qualquer valor JSON
+	}
+}
+
+// structSchema gera o schema de objeto com os campos exportados da struct, incluindo os de structs embutidas.
+func (g *openAPIGenerator) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	g.addFields(schema, t)
+	return schema
+}
+
+func (g *openAPIGenerator) addFields(schema *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct && field.Type != puzzleResponseType {
+			g.addFields(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type)
+		if field.Tag.Get("openapi") == "puzzle" {
+			prop = g.puzzleSchema() // JSON bruto com o formato de GeminiPuzzleResponse (ex: PuzzleEnvelope.Puzzle).
+		}
+		if desc, enum := field.Tag.Get("description"), field.Tag.Get("enum"); desc != "" || enum != "" {
+			if prop.Ref != "" {
+				prop = &openAPISchema{OneOf: []*openAPISchema{prop}} // $ref não admite campos irmãos no OpenAPI 3.0.
+			}
+			prop.Description = desc
+			if enum != "" {
+				prop.Enum = strings.Split(enum, ",")
+			}
+		}
+		if field.Tag.Get("openapi") == "gameType" {
+			prop.Enum = gameTypeNames()
+		}
+		schema.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// puzzleSchema registra o componente Puzzle: um oneOf com um objeto por tipo de jogo registrado, com os
+// campos comuns de GeminiPuzzleResponse e o campo de dados do tipo (ex: crosswordData), discriminado por gameType.
+func (g *openAPIGenerator) puzzleSchema() *openAPISchema {
+	ref := &openAPISchema{Ref: "#/components/schemas/" + puzzleComponentName}
+	if _, ok := g.schemas[puzzleComponentName]; ok {
+		return ref
+	}
+	puzzle := &openAPISchema{Discriminator: &openAPIDiscriminator{PropertyName: "gameType", Mapping: map[string]string{}}}
+	g.schemas[puzzleComponentName] = puzzle
+
+	for _, name := range gameTypeNames() {
+		gt, _ := lookupGameType(name)
+		component := strings.TrimSuffix(gt.dataType.Name(), "Data") + "Puzzle" // Ex: CrosswordData -> CrosswordPuzzle
+		schema := g.structSchema(reflect.TypeOf(geminiPuzzleResponseFields{}))
+		schema.Properties["gameType"] = &openAPISchema{Type: "string", Enum: []string{name}}
+		schema.Properties[gt.dataField] = g.schemaFor(gt.dataType)
+		schema.Required = append(schema.Required, gt.dataField)
+		sort.Strings(schema.Required)
+		g.schemas[component] = schema
+
+		componentRef := "#/components/schemas/" + component
+		puzzle.OneOf = append(puzzle.OneOf, &openAPISchema{Ref: componentRef})
+		puzzle.Discriminator.Mapping[name] = componentRef
+	}
+	return ref
+}
+
+// openAPIHandler é o manipulador de GET /openapi.json.
+func (s *Server) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	writeCacheable(w, r, s.openAPIETag, s.openAPIDoc)
+}
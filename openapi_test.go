@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pathParamPattern reconhece os parâmetros de caminho dos padrões do ServeMux, como {id}.
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// A especificação servida em /openapi.json deve descrever exatamente as rotas registradas, com os status
+// de erro declarados, e o enum de gameType deve acompanhar o registro de tipos de jogo.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	s := &Server{store: NewMemoryStore(), adminToken: "segredo"}
+	handler := s.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d, quer 200", rec.Code)
+	}
+	var doc openAPIDocument
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("especificação inválida: %v", err)
+	}
+
+	routes := s.apiRoutes()
+	operations := 0
+	for _, ops := range doc.Paths {
+		operations += len(ops)
+	}
+	if operations != len(routes) {
+		t.Errorf("a especificação tem %d operações, quer %d (uma por rota)", operations, len(routes))
+	}
+
+	for _, rt := range routes {
+		pattern := rt.Method + " " + rt.Path
+		op := doc.Paths[rt.Path][strings.ToLower(rt.Method)]
+		if op == nil {
+			t.Errorf("%s: sem operação na especificação", pattern)
+			continue
+		}
+		if op.OperationID != rt.Name {
+			t.Errorf("%s: operationId = %q, quer %q", pattern, op.OperationID, rt.Name)
+		}
+
+		status := rt.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		statuses := []int{status}
+		for _, e := range rt.Errors {
+			statuses = append(statuses, e.Status)
+		}
+		if rt.Admin {
+			statuses = append(statuses, http.StatusUnauthorized)
+		}
+		for _, status := range statuses {
+			if op.Responses[strconv.Itoa(status)] == nil {
+				t.Errorf("%s: status %d ausente das respostas", pattern, status)
+			}
+		}
+
+		params := map[string]bool{}
+		for _, p := range op.Parameters {
+			if p.In == "path" {
+				params[p.Name] = true
+			}
+		}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(rt.Path, -1) {
+			if !params[m[1]] {
+				t.Errorf("%s: parâmetro de caminho %q não documentado", pattern, m[1])
+			}
+		}
+		if (rt.Request != nil) != (op.RequestBody != nil) {
+			t.Errorf("%s: requestBody documentado = %v, quer %v", pattern, op.RequestBody != nil, rt.Request != nil)
+		}
+	}
+
+	for _, name := range []string{"PuzzleRequest", "JobRequest"} {
+		schema := doc.Components.Schemas[name]
+		if schema == nil || schema.Properties["gameType"] == nil {
+			t.Errorf("%s.gameType ausente da especificação", name)
+			continue
+		}
+		if got := schema.Properties["gameType"].Enum; !reflect.DeepEqual(got, gameTypeNames()) {
+			t.Errorf("%s.gameType enum = %v, quer %v", name, got, gameTypeNames())
+		}
+	}
+}
+
+// errStoreUnavailable é o erro do failingStore.
+var errStoreUnavailable = errors.New("store indisponível")
+
+// failingStore é um MemoryStore que, com fail ligado, falha nas consultas e gravações de quebra-cabeças
+// e de entregas de webhooks, para exercitar as respostas 500.
+type failingStore struct {
+	CacheStore
+	fail atomic.Bool
+}
+
+func (f *failingStore) GetCachedPuzzleEntry(requestHash string) (*CachedPuzzle, error) {
+	if f.fail.Load() {
+		return nil, errStoreUnavailable
+	}
+	return f.CacheStore.GetCachedPuzzleEntry(requestHash)
+}
+
+func (f *failingStore) GetCachedPuzzleByID(puzzleID string) (*CachedPuzzle, error) {
+	if f.fail.Load() {
+		return nil, errStoreUnavailable
+	}
+	return f.CacheStore.GetCachedPuzzleByID(puzzleID)
+}
+
+func (f *failingStore) ListCachedPuzzles(filter CacheFilter) ([]CachedPuzzle, error) {
+	if f.fail.Load() {
+		return nil, errStoreUnavailable
+	}
+	return f.CacheStore.ListCachedPuzzles(filter)
+}
+
+func (f *failingStore) DeleteCachedPuzzle(requestHash string) (bool, error) {
+	if f.fail.Load() {
+		return false, errStoreUnavailable
+	}
+	return f.CacheStore.DeleteCachedPuzzle(requestHash)
+}
+
+func (f *failingStore) PurgeCachedPuzzles(filter CacheFilter) (int64, error) {
+	if f.fail.Load() {
+		return 0, errStoreUnavailable
+	}
+	return f.CacheStore.PurgeCachedPuzzles(filter)
+}
+
+func (f *failingStore) ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	if f.fail.Load() {
+		return nil, errStoreUnavailable
+	}
+	return f.CacheStore.ListWebhookDeliveries(filter)
+}
+
+// Cada rota, exercitada pelo mux de routes com um store e um Gemini falsos, deve responder apenas com
+// status e Content-Type declarados na especificação; métodos não documentados recebem 405 com o Allow
+// listando exatamente as operações do caminho.
+func TestRoutesRespondAsDocumented(t *testing.T) {
+	store := &failingStore{CacheStore: NewMemoryStore()}
+	gemini := &fakeGemini{replies: []fakeGeminiReply{
+		{status: http.StatusInternalServerError, body: `{}`},
+		{status: http.StatusTooManyRequests, body: `{}`},
+	}}
+	s := &Server{
+		store:                store,
+		geminiPuzzleService:  newTestGeminiService(t, gemini, []string{"chave"}, []string{"gemini-test"}, 1),
+		adminToken:           "segredo",
+		idempotencyRetention: time.Hour,
+		daily:                DailyConfig{GameTypes: []string{"sudoku"}, Languages: []string{"en"}, Difficulty: "easy", Topics: []string{"numbers"}, Location: time.UTC},
+	}
+	s.webhooks = newWebhookSender(WebhookConfig{}, store) // Sem WEBHOOK_SECRET: todo callbackUrl é recusado.
+	s.jobs = NewJobManager(JobsConfig{MaxConcurrent: 1, Retention: time.Hour}, s.generate, nil)
+	handler := s.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc openAPIDocument
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("especificação inválida: %v", err)
+	}
+
+	sudoku := PuzzleRequest{GameType: "sudoku", Difficulty: "easy"}
+	puzzle, err := s.generate(context.Background(), sudoku)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.jobs.Create(sudoku, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		sudokuBody    = `{"gameType":"sudoku","difficulty":"easy"}`
+		crosswordBody = `{"gameType":"crossword","difficulty":"easy","topics":["animais"],"language":"pt"}`
+		admin         = "Bearer segredo"
+	)
+	longKey := strings.Repeat("k", maxIdempotencyKeyLength+1)
+	tests := []struct {
+		name       string // operationId
+		target     string
+		header     map[string]string
+		body       string
+		storeFails bool
+		want       int
+	}{
+		{name: "generatePuzzle", target: "/generate-puzzle", body: sudokuBody, want: http.StatusOK},
+		{name: "generatePuzzle", target: "/generate-puzzle", body: `{`, want: http.StatusBadRequest},
+		{name: "generatePuzzle", target: "/generate-puzzle", body: `{"gameType":"chess"}`, want: http.StatusBadRequest},
+		{name: "generatePuzzle", target: "/generate-puzzle", body: crosswordBody, want: http.StatusBadGateway},
+		{name: "generatePuzzle", target: "/generate-puzzle", body: crosswordBody, want: http.StatusServiceUnavailable},
+		{name: "generatePuzzle", target: "/generate-puzzle", header: map[string]string{"Idempotency-Key": longKey}, body: sudokuBody, want: http.StatusBadRequest},
+		{name: "generatePuzzle", target: "/generate-puzzle", header: map[string]string{"Idempotency-Key": "k1"}, body: sudokuBody, want: http.StatusOK},
+		{name: "generatePuzzle", target: "/generate-puzzle", header: map[string]string{"Idempotency-Key": "k1"}, body: `{"gameType":"sudoku","difficulty":"hard"}`, want: http.StatusUnprocessableEntity},
+		{name: "getPuzzle", target: "/puzzles/" + puzzle.PuzzleID, want: http.StatusOK},
+		{name: "getPuzzle", target: "/puzzles/" + puzzle.PuzzleID, header: map[string]string{"If-None-Match": puzzle.etag()}, want: http.StatusNotModified},
+		{name: "getPuzzle", target: "/puzzles/desconhecido", want: http.StatusNotFound},
+		{name: "getPuzzle", target: "/puzzles/" + puzzle.PuzzleID, storeFails: true, want: http.StatusInternalServerError},
+		{name: "getDailyPuzzle", target: "/daily", want: http.StatusOK},
+		{name: "getDailyPuzzle", target: "/daily", header: map[string]string{"If-None-Match": "*"}, want: http.StatusNotModified},
+		{name: "getDailyPuzzle", target: "/daily?tz=Marte/Olympus", want: http.StatusBadRequest},
+		{name: "getDailyPuzzle", target: "/daily?gameType=wordsearch", want: http.StatusBadRequest},
+		{name: "createJob", target: "/jobs", body: sudokuBody, want: http.StatusAccepted},
+		{name: "createJob", target: "/jobs", body: `{"gameType":"sudoku","callbackUrl":"https://example.com/hook"}`, want: http.StatusBadRequest},
+		{name: "createJob", target: "/jobs", header: map[string]string{"Idempotency-Key": "k2"}, body: sudokuBody, want: http.StatusAccepted},
+		{name: "createJob", target: "/jobs", header: map[string]string{"Idempotency-Key": "k2"}, body: crosswordBody, want: http.StatusUnprocessableEntity},
+		{name: "getJob", target: "/jobs/" + job.ID, want: http.StatusOK},
+		{name: "getJob", target: "/jobs/desconhecido", want: http.StatusNotFound},
+		{name: "generatePuzzleV1", target: "/v1/puzzles", body: sudokuBody, want: http.StatusOK},
+		{name: "generatePuzzleV1", target: "/v1/puzzles", body: `{"gameType":"chess"}`, want: http.StatusBadRequest},
+		{name: "generatePuzzleV1", target: "/v1/puzzles", header: map[string]string{"Idempotency-Key": longKey}, body: sudokuBody, want: http.StatusBadRequest},
+		{name: "getPuzzleV1", target: "/v1/puzzles/" + puzzle.PuzzleID, want: http.StatusOK},
+		{name: "getPuzzleV1", target: "/v1/puzzles/" + puzzle.PuzzleID, header: map[string]string{"If-None-Match": "*"}, want: http.StatusNotModified},
+		{name: "getPuzzleV1", target: "/v1/puzzles/desconhecido", want: http.StatusNotFound},
+		{name: "getPuzzleV1", target: "/v1/puzzles/" + puzzle.PuzzleID, storeFails: true, want: http.StatusInternalServerError},
+		{name: "getDailyPuzzleV1", target: "/v1/daily", want: http.StatusOK},
+		{name: "getDailyPuzzleV1", target: "/v1/daily?language=xx", want: http.StatusBadRequest},
+		{name: "createJobV1", target: "/v1/jobs", body: sudokuBody, want: http.StatusAccepted},
+		{name: "createJobV1", target: "/v1/jobs", body: `{`, want: http.StatusBadRequest},
+		{name: "getJobV1", target: "/v1/jobs/" + job.ID, want: http.StatusOK},
+		{name: "getJobV1", target: "/v1/jobs/desconhecido", want: http.StatusNotFound},
+		{name: "getOpenAPI", target: "/openapi.json", want: http.StatusOK},
+		{name: "getOpenAPI", target: "/openapi.json", header: map[string]string{"If-None-Match": "*"}, want: http.StatusNotModified},
+		{name: "adminListPuzzles", target: "/admin/puzzles", header: map[string]string{"Authorization": admin}, want: http.StatusOK},
+		{name: "adminListPuzzles", target: "/admin/puzzles", want: http.StatusUnauthorized},
+		{name: "adminListPuzzles", target: "/admin/puzzles?limit=x", header: map[string]string{"Authorization": admin}, want: http.StatusBadRequest},
+		{name: "adminListPuzzles", target: "/admin/puzzles", header: map[string]string{"Authorization": admin}, storeFails: true, want: http.StatusInternalServerError},
+		{name: "adminGetPuzzle", target: "/admin/puzzles/" + puzzle.Entry.RequestHash, header: map[string]string{"Authorization": admin}, want: http.StatusOK},
+		{name: "adminGetPuzzle", target: "/admin/puzzles/desconhecido", header: map[string]string{"Authorization": admin}, want: http.StatusNotFound},
+		{name: "adminGetPuzzle", target: "/admin/puzzles/desconhecido", header: map[string]string{"Authorization": admin}, storeFails: true, want: http.StatusInternalServerError},
+		{name: "adminDeletePuzzle", target: "/admin/puzzles/desconhecido", header: map[string]string{"Authorization": admin}, want: http.StatusNotFound},
+		{name: "adminDeletePuzzle", target: "/admin/puzzles/desconhecido", header: map[string]string{"Authorization": admin}, storeFails: true, want: http.StatusInternalServerError},
+		{name: "adminDeletePuzzle", target: "/admin/puzzles/" + puzzle.Entry.RequestHash, header: map[string]string{"Authorization": admin}, want: http.StatusNoContent},
+		{name: "adminPurgePuzzles", target: "/admin/puzzles?gameType=sudoku", header: map[string]string{"Authorization": admin}, want: http.StatusOK},
+		{name: "adminPurgePuzzles", target: "/admin/puzzles", header: map[string]string{"Authorization": admin}, want: http.StatusBadRequest},
+		{name: "adminPurgePuzzles", target: "/admin/puzzles?all=true", header: map[string]string{"Authorization": admin}, storeFails: true, want: http.StatusInternalServerError},
+		{name: "adminListWebhookDeliveries", target: "/admin/webhook-deliveries", header: map[string]string{"Authorization": admin}, want: http.StatusOK},
+		{name: "adminListWebhookDeliveries", target: "/admin/webhook-deliveries?offset=-1", header: map[string]string{"Authorization": admin}, want: http.StatusBadRequest},
+		{name: "adminListWebhookDeliveries", target: "/admin/webhook-deliveries", header: map[string]string{"Authorization": admin}, storeFails: true, want: http.StatusInternalServerError},
+		{name: "adminMetrics", target: "/admin/metrics", header: map[string]string{"Authorization": admin}, want: http.StatusOK},
+	}
+
+	routes := map[string]apiRoute{}
+	for _, rt := range s.apiRoutes() {
+		routes[rt.Name] = rt
+	}
+	exercised := map[string]bool{}
+	for _, tt := range tests {
+		rt := routes[tt.name]
+		exercised[tt.name] = true
+		req := httptest.NewRequest(rt.Method, tt.target, strings.NewReader(tt.body))
+		for name, value := range tt.header {
+			req.Header.Set(name, value)
+		}
+		store.fail.Store(tt.storeFails)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		store.fail.Store(false)
+
+		desc := rt.Method + " " + tt.target
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, quer %d: %s", desc, rec.Code, tt.want, rec.Body)
+			continue
+		}
+		resp := doc.Paths[rt.Path][strings.ToLower(rt.Method)].Responses[strconv.Itoa(rec.Code)]
+		if resp == nil {
+			t.Errorf("%s: status %d não declarado na especificação", desc, rec.Code)
+			continue
+		}
+		contentType := rec.Header().Get("Content-Type")
+		if len(resp.Content) == 0 {
+			if contentType != "" || rec.Body.Len() > 0 {
+				t.Errorf("%s: status %d declarado sem corpo, mas a resposta tem Content-Type %q e %d bytes", desc, rec.Code, contentType, rec.Body.Len())
+			}
+			continue
+		}
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if _, ok := resp.Content[mediaType]; !ok {
+			t.Errorf("%s: Content-Type %q não declarado para o status %d", desc, contentType, rec.Code)
+		}
+	}
+	for name := range routes {
+		if !exercised[name] {
+			t.Errorf("rota %s não exercitada", name)
+		}
+	}
+
+	for path, ops := range doc.Paths {
+		allowed := []string{}
+		for method := range ops {
+			allowed = append(allowed, strings.ToUpper(method))
+			if method == "get" {
+				allowed = append(allowed, http.MethodHead) // O ServeMux aceita HEAD nos padrões GET.
+			}
+		}
+		sort.Strings(allowed)
+		target := pathParamPattern.ReplaceAllString(path, "x")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, target, nil))
+		got := strings.Split(rec.Header().Get("Allow"), ", ")
+		sort.Strings(got)
+		if rec.Code != http.StatusMethodNotAllowed || !reflect.DeepEqual(got, allowed) {
+			t.Errorf("PATCH %s: status %d com Allow %v, quer 405 com %v", target, rec.Code, got, allowed)
+		}
+	}
+}
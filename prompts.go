@@ -0,0 +1,128 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// embeddedPrompts contém os templates de prompt padrão, um por tipo de jogo (prompts/<nome>.tmpl).
+//
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+// repairPromptName é o template usado para pedir ao Gemini que corrija uma resposta inválida.
+// Recebe os mesmos dados dos prompts dos jogos, com Errors preenchido.
+const repairPromptName = "repair"
+
+// promptVersionPattern reconhece o cabeçalho obrigatório de versão na primeira linha do template,
+// um comentário de template como "{{/* version: 2 */ -}}".
+var promptVersionPattern = regexp.MustCompile(`^\{\{-?\s*/\*\s*version:\s*([^\s*]+)\s*\*/\s*-?\}\}`)
+
+// promptTemplate é um template de prompt carregado, com a versão declarada no cabeçalho.
+type promptTemplate struct {
+	tmpl    *template.Template
+	version string
+	source  string // Origem do arquivo ("embutido" ou o caminho no disco), usada nos logs
+}
+
+// PromptSet contém os templates de prompt dos tipos de jogo, indexados pelo nome do arquivo sem ".tmpl".
+// Os templates recebem um promptParams como dados.
+type PromptSet struct {
+	templates map[string]*promptTemplate
+}
+
+// LoadPrompts carrega os templates embutidos e, se dir não for vazio, os arquivos .tmpl de dir, que substituem
+// os embutidos de mesmo nome. Assim o texto dos prompts pode ser ajustado sem recompilar o binário.
+// Retorna erro se algum template for inválido, não declarar a versão ou se faltar o template de reparo
+// ou o de algum tipo de jogo registrado que usa o Gemini.
+func LoadPrompts(dir string) (*PromptSet, error) {
+	set := &PromptSet{templates: map[string]*promptTemplate{}}
+	if err := set.loadFS(embeddedPrompts, "prompts", "embutido"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := set.loadFS(os.DirFS(dir), ".", dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := set.templates[repairPromptName]; !ok {
+		return nil, fmt.Errorf("template de prompt %q não encontrado", repairPromptName+".tmpl")
+	}
+	for _, name := range gameTypeNames() {
+		gt, _ := lookupGameType(name)
+		if gt.isLocal() {
+			continue
+		}
+		p, ok := set.templates[gt.prompt]
+		if !ok {
+			return nil, fmt.Errorf("template de prompt %q do tipo de jogo %q não encontrado", gt.prompt+".tmpl", name)
+		}
+		log.Printf("Prompt %s: versão %s (%s)", gt.prompt, p.version, p.source)
+	}
+	return set, nil
+}
+
+// loadFS carrega os arquivos .tmpl do diretório dir de fsys, substituindo templates de mesmo nome.
+func (s *PromptSet) loadFS(fsys fs.FS, dir, source string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.tmpl"))
+	if err != nil {
+		return fmt.Errorf("falha ao listar os templates de prompt em %s: %w", source, err)
+	}
+	for _, file := range files {
+		text, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("falha ao ler o template de prompt %s: %w", file, err)
+		}
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		match := promptVersionPattern.FindSubmatch(text)
+		if match == nil {
+			return fmt.Errorf("template de prompt %s sem o cabeçalho de versão {{/* version: N */ -}}", file)
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return fmt.Errorf("template de prompt %s inválido: %w", file, err)
+		}
+		// Executa o template uma vez para detectar na inicialização campos inexistentes em promptParams.
+		if err := tmpl.Execute(io.Discard, promptParams{}); err != nil {
+			return fmt.Errorf("template de prompt %s inválido: %w", file, err)
+		}
+
+		fileSource := source
+		if source != "embutido" {
+			fileSource = path.Join(source, file)
+		}
+		s.templates[name] = &promptTemplate{tmpl: tmpl, version: string(match[1]), source: fileSource}
+	}
+	return nil
+}
+
+// Version retorna a versão do template, ou "" se ele não existir.
+func (s *PromptSet) Version(name string) string {
+	if p, ok := s.templates[name]; ok {
+		return p.version
+	}
+	return ""
+}
+
+// Render executa o template com os parâmetros e retorna o prompt.
+func (s *PromptSet) Render(name string, params promptParams) (string, error) {
+	p, ok := s.templates[name]
+	if !ok {
+		return "", fmt.Errorf("template de prompt %q não encontrado", name)
+	}
+	var b strings.Builder
+	if err := p.tmpl.Execute(&b, params); err != nil {
+		return "", fmt.Errorf("falha ao executar o template de prompt %q: %w", name, err)
+	}
+	return b.String(), nil
+}
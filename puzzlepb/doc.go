@@ -0,0 +1,4 @@
+// Package puzzlepb contém o código gerado a partir de puzzle.proto para a API gRPC do proxy.
+package puzzlepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative puzzle.proto
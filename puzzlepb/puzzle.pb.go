@@ -0,0 +1,2001 @@
+// API gRPC do proxy de quebra-cabeças. As mensagens espelham PuzzleRequest e GeminiPuzzleResponse
+// (models.go); os nomes JSON dos campos são os mesmos da API HTTP.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: puzzle.proto
+
+package puzzlepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type JobStatus int32
+
+const (
+	JobStatus_JOB_STATUS_UNSPECIFIED JobStatus = 0
+	JobStatus_JOB_STATUS_PENDING     JobStatus = 1
+	JobStatus_JOB_STATUS_RUNNING     JobStatus = 2
+	JobStatus_JOB_STATUS_SUCCEEDED   JobStatus = 3
+	JobStatus_JOB_STATUS_FAILED      JobStatus = 4
+)
+
+// Enum value maps for JobStatus.
+var (
+	JobStatus_name = map[int32]string{
+		0: "JOB_STATUS_UNSPECIFIED",
+		1: "JOB_STATUS_PENDING",
+		2: "JOB_STATUS_RUNNING",
+		3: "JOB_STATUS_SUCCEEDED",
+		4: "JOB_STATUS_FAILED",
+	}
+	JobStatus_value = map[string]int32{
+		"JOB_STATUS_UNSPECIFIED": 0,
+		"JOB_STATUS_PENDING":     1,
+		"JOB_STATUS_RUNNING":     2,
+		"JOB_STATUS_SUCCEEDED":   3,
+		"JOB_STATUS_FAILED":      4,
+	}
+)
+
+func (x JobStatus) Enum() *JobStatus {
+	p := new(JobStatus)
+	*p = x
+	return p
+}
+
+func (x JobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_puzzle_proto_enumTypes[0].Descriptor()
+}
+
+func (JobStatus) Type() protoreflect.EnumType {
+	return &file_puzzle_proto_enumTypes[0]
+}
+
+func (x JobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobStatus.Descriptor instead.
+func (JobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{0}
+}
+
+type PuzzleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameType    string   `protobuf:"bytes,1,opt,name=game_type,json=gameType,proto3" json:"game_type,omitempty"`
+	Difficulty  string   `protobuf:"bytes,2,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	Topics      []string `protobuf:"bytes,3,rep,name=topics,proto3" json:"topics,omitempty"`
+	Language    string   `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	CallbackUrl string   `protobuf:"bytes,5,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"` // Apenas em CreateJob: recebe um POST assinado quando o job termina (exige WEBHOOK_SECRET)
+}
+
+func (x *PuzzleRequest) Reset() {
+	*x = PuzzleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PuzzleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PuzzleRequest) ProtoMessage() {}
+
+func (x *PuzzleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PuzzleRequest.ProtoReflect.Descriptor instead.
+func (*PuzzleRequest) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PuzzleRequest) GetGameType() string {
+	if x != nil {
+		return x.GameType
+	}
+	return ""
+}
+
+func (x *PuzzleRequest) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *PuzzleRequest) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *PuzzleRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *PuzzleRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+type GetPuzzleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PuzzleId string `protobuf:"bytes,1,opt,name=puzzle_id,json=puzzleId,proto3" json:"puzzle_id,omitempty"`
+}
+
+func (x *GetPuzzleRequest) Reset() {
+	*x = GetPuzzleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPuzzleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPuzzleRequest) ProtoMessage() {}
+
+func (x *GetPuzzleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPuzzleRequest.ProtoReflect.Descriptor instead.
+func (*GetPuzzleRequest) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPuzzleRequest) GetPuzzleId() string {
+	if x != nil {
+		return x.PuzzleId
+	}
+	return ""
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Puzzle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PuzzleId   string   `protobuf:"bytes,1,opt,name=puzzle_id,json=puzzleId,proto3" json:"puzzle_id,omitempty"`
+	GameType   string   `protobuf:"bytes,2,opt,name=game_type,json=gameType,proto3" json:"game_type,omitempty"`
+	Difficulty string   `protobuf:"bytes,3,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	Topics     []string `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"`
+	PuzzleDate string   `protobuf:"bytes,5,opt,name=puzzle_date,json=puzzleDate,proto3" json:"puzzle_date,omitempty"`
+	Cached     bool     `protobuf:"varint,6,opt,name=cached,proto3" json:"cached,omitempty"` // true se o quebra-cabeça veio do cache
+	// Dados do jogo; o campo preenchido corresponde a game_type.
+	//
+	// Types that are assignable to Data:
+	//	*Puzzle_CrosswordData
+	//	*Puzzle_WordSearchData
+	//	*Puzzle_SudokuData
+	//	*Puzzle_QuizData
+	//	*Puzzle_WordScrambleData
+	//	*Puzzle_CryptogramData
+	//	*Puzzle_WordLadderData
+	//	*Puzzle_ConnectionsData
+	Data isPuzzle_Data `protobuf_oneof:"data"`
+}
+
+func (x *Puzzle) Reset() {
+	*x = Puzzle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Puzzle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Puzzle) ProtoMessage() {}
+
+func (x *Puzzle) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Puzzle.ProtoReflect.Descriptor instead.
+func (*Puzzle) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Puzzle) GetPuzzleId() string {
+	if x != nil {
+		return x.PuzzleId
+	}
+	return ""
+}
+
+func (x *Puzzle) GetGameType() string {
+	if x != nil {
+		return x.GameType
+	}
+	return ""
+}
+
+func (x *Puzzle) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *Puzzle) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *Puzzle) GetPuzzleDate() string {
+	if x != nil {
+		return x.PuzzleDate
+	}
+	return ""
+}
+
+func (x *Puzzle) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (m *Puzzle) GetData() isPuzzle_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *Puzzle) GetCrosswordData() *CrosswordData {
+	if x, ok := x.GetData().(*Puzzle_CrosswordData); ok {
+		return x.CrosswordData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetWordSearchData() *WordSearchData {
+	if x, ok := x.GetData().(*Puzzle_WordSearchData); ok {
+		return x.WordSearchData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetSudokuData() *SudokuData {
+	if x, ok := x.GetData().(*Puzzle_SudokuData); ok {
+		return x.SudokuData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetQuizData() *QuizData {
+	if x, ok := x.GetData().(*Puzzle_QuizData); ok {
+		return x.QuizData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetWordScrambleData() *WordScrambleData {
+	if x, ok := x.GetData().(*Puzzle_WordScrambleData); ok {
+		return x.WordScrambleData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetCryptogramData() *CryptogramData {
+	if x, ok := x.GetData().(*Puzzle_CryptogramData); ok {
+		return x.CryptogramData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetWordLadderData() *WordLadderData {
+	if x, ok := x.GetData().(*Puzzle_WordLadderData); ok {
+		return x.WordLadderData
+	}
+	return nil
+}
+
+func (x *Puzzle) GetConnectionsData() *ConnectionsData {
+	if x, ok := x.GetData().(*Puzzle_ConnectionsData); ok {
+		return x.ConnectionsData
+	}
+	return nil
+}
+
+type isPuzzle_Data interface {
+	isPuzzle_Data()
+}
+
+type Puzzle_CrosswordData struct {
+	CrosswordData *CrosswordData `protobuf:"bytes,10,opt,name=crossword_data,json=crosswordData,proto3,oneof"`
+}
+
+type Puzzle_WordSearchData struct {
+	WordSearchData *WordSearchData `protobuf:"bytes,11,opt,name=word_search_data,json=wordSearchData,proto3,oneof"`
+}
+
+type Puzzle_SudokuData struct {
+	SudokuData *SudokuData `protobuf:"bytes,12,opt,name=sudoku_data,json=sudokuData,proto3,oneof"`
+}
+
+type Puzzle_QuizData struct {
+	QuizData *QuizData `protobuf:"bytes,13,opt,name=quiz_data,json=quizData,proto3,oneof"`
+}
+
+type Puzzle_WordScrambleData struct {
+	WordScrambleData *WordScrambleData `protobuf:"bytes,14,opt,name=word_scramble_data,json=wordScrambleData,proto3,oneof"`
+}
+
+type Puzzle_CryptogramData struct {
+	CryptogramData *CryptogramData `protobuf:"bytes,15,opt,name=cryptogram_data,json=cryptogramData,proto3,oneof"`
+}
+
+type Puzzle_WordLadderData struct {
+	WordLadderData *WordLadderData `protobuf:"bytes,16,opt,name=word_ladder_data,json=wordLadderData,proto3,oneof"`
+}
+
+type Puzzle_ConnectionsData struct {
+	ConnectionsData *ConnectionsData `protobuf:"bytes,17,opt,name=connections_data,json=connectionsData,proto3,oneof"`
+}
+
+func (*Puzzle_CrosswordData) isPuzzle_Data() {}
+
+func (*Puzzle_WordSearchData) isPuzzle_Data() {}
+
+func (*Puzzle_SudokuData) isPuzzle_Data() {}
+
+func (*Puzzle_QuizData) isPuzzle_Data() {}
+
+func (*Puzzle_WordScrambleData) isPuzzle_Data() {}
+
+func (*Puzzle_CryptogramData) isPuzzle_Data() {}
+
+func (*Puzzle_WordLadderData) isPuzzle_Data() {}
+
+func (*Puzzle_ConnectionsData) isPuzzle_Data() {}
+
+type GridSize struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rows int32 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols int32 `protobuf:"varint,2,opt,name=cols,proto3" json:"cols,omitempty"`
+}
+
+func (x *GridSize) Reset() {
+	*x = GridSize{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GridSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GridSize) ProtoMessage() {}
+
+func (x *GridSize) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GridSize.ProtoReflect.Descriptor instead.
+func (*GridSize) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GridSize) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *GridSize) GetCols() int32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+type CrosswordWord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Word      string `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Clue      string `protobuf:"bytes,2,opt,name=clue,proto3" json:"clue,omitempty"`
+	StartRow  int32  `protobuf:"varint,3,opt,name=start_row,json=startRow,proto3" json:"start_row,omitempty"`
+	StartCol  int32  `protobuf:"varint,4,opt,name=start_col,json=startCol,proto3" json:"start_col,omitempty"`
+	Direction string `protobuf:"bytes,5,opt,name=direction,proto3" json:"direction,omitempty"` // "across" ou "down"
+}
+
+func (x *CrosswordWord) Reset() {
+	*x = CrosswordWord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CrosswordWord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrosswordWord) ProtoMessage() {}
+
+func (x *CrosswordWord) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrosswordWord.ProtoReflect.Descriptor instead.
+func (*CrosswordWord) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CrosswordWord) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *CrosswordWord) GetClue() string {
+	if x != nil {
+		return x.Clue
+	}
+	return ""
+}
+
+func (x *CrosswordWord) GetStartRow() int32 {
+	if x != nil {
+		return x.StartRow
+	}
+	return 0
+}
+
+func (x *CrosswordWord) GetStartCol() int32 {
+	if x != nil {
+		return x.StartCol
+	}
+	return 0
+}
+
+func (x *CrosswordWord) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type CrosswordData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GridSize *GridSize        `protobuf:"bytes,1,opt,name=grid_size,json=gridSize,proto3" json:"grid_size,omitempty"`
+	Words    []*CrosswordWord `protobuf:"bytes,2,rep,name=words,proto3" json:"words,omitempty"`
+}
+
+func (x *CrosswordData) Reset() {
+	*x = CrosswordData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CrosswordData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrosswordData) ProtoMessage() {}
+
+func (x *CrosswordData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrosswordData.ProtoReflect.Descriptor instead.
+func (*CrosswordData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CrosswordData) GetGridSize() *GridSize {
+	if x != nil {
+		return x.GridSize
+	}
+	return nil
+}
+
+func (x *CrosswordData) GetWords() []*CrosswordWord {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+type WordSearchData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GridSize    *GridSize `protobuf:"bytes,1,opt,name=grid_size,json=gridSize,proto3" json:"grid_size,omitempty"`
+	WordsToFind []string  `protobuf:"bytes,2,rep,name=words_to_find,json=wordsToFind,proto3" json:"words_to_find,omitempty"`
+}
+
+func (x *WordSearchData) Reset() {
+	*x = WordSearchData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WordSearchData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordSearchData) ProtoMessage() {}
+
+func (x *WordSearchData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordSearchData.ProtoReflect.Descriptor instead.
+func (*WordSearchData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WordSearchData) GetGridSize() *GridSize {
+	if x != nil {
+		return x.GridSize
+	}
+	return nil
+}
+
+func (x *WordSearchData) GetWordsToFind() []string {
+	if x != nil {
+		return x.WordsToFind
+	}
+	return nil
+}
+
+// Uma linha da grade do sudoku. Na API HTTP, as grades são arrays de arrays de números.
+type SudokuRow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cells []int32 `protobuf:"varint,1,rep,packed,name=cells,proto3" json:"cells,omitempty"` // 9 números; 0 indica uma célula vazia
+}
+
+func (x *SudokuRow) Reset() {
+	*x = SudokuRow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SudokuRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SudokuRow) ProtoMessage() {}
+
+func (x *SudokuRow) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SudokuRow.ProtoReflect.Descriptor instead.
+func (*SudokuRow) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SudokuRow) GetCells() []int32 {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+type SudokuData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Puzzle     []*SudokuRow `protobuf:"bytes,1,rep,name=puzzle,proto3" json:"puzzle,omitempty"`     // 9 linhas com as pistas
+	Solution   []*SudokuRow `protobuf:"bytes,2,rep,name=solution,proto3" json:"solution,omitempty"` // 9 linhas resolvidas
+	Givens     int32        `protobuf:"varint,3,opt,name=givens,proto3" json:"givens,omitempty"`
+	Techniques []string     `protobuf:"bytes,4,rep,name=techniques,proto3" json:"techniques,omitempty"`
+}
+
+func (x *SudokuData) Reset() {
+	*x = SudokuData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SudokuData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SudokuData) ProtoMessage() {}
+
+func (x *SudokuData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SudokuData.ProtoReflect.Descriptor instead.
+func (*SudokuData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SudokuData) GetPuzzle() []*SudokuRow {
+	if x != nil {
+		return x.Puzzle
+	}
+	return nil
+}
+
+func (x *SudokuData) GetSolution() []*SudokuRow {
+	if x != nil {
+		return x.Solution
+	}
+	return nil
+}
+
+func (x *SudokuData) GetGivens() int32 {
+	if x != nil {
+		return x.Givens
+	}
+	return 0
+}
+
+func (x *SudokuData) GetTechniques() []string {
+	if x != nil {
+		return x.Techniques
+	}
+	return nil
+}
+
+type QuizQuestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Question     string   `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	Options      []string `protobuf:"bytes,2,rep,name=options,proto3" json:"options,omitempty"`
+	CorrectIndex int32    `protobuf:"varint,3,opt,name=correct_index,json=correctIndex,proto3" json:"correct_index,omitempty"`
+	Explanation  string   `protobuf:"bytes,4,opt,name=explanation,proto3" json:"explanation,omitempty"`
+}
+
+func (x *QuizQuestion) Reset() {
+	*x = QuizQuestion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuizQuestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizQuestion) ProtoMessage() {}
+
+func (x *QuizQuestion) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizQuestion.ProtoReflect.Descriptor instead.
+func (*QuizQuestion) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *QuizQuestion) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *QuizQuestion) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *QuizQuestion) GetCorrectIndex() int32 {
+	if x != nil {
+		return x.CorrectIndex
+	}
+	return 0
+}
+
+func (x *QuizQuestion) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+type QuizData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Questions []*QuizQuestion `protobuf:"bytes,1,rep,name=questions,proto3" json:"questions,omitempty"`
+}
+
+func (x *QuizData) Reset() {
+	*x = QuizData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuizData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizData) ProtoMessage() {}
+
+func (x *QuizData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizData.ProtoReflect.Descriptor instead.
+func (*QuizData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QuizData) GetQuestions() []*QuizQuestion {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+type ScrambleWord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Word      string `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Hint      string `protobuf:"bytes,2,opt,name=hint,proto3" json:"hint,omitempty"`
+	Scrambled string `protobuf:"bytes,3,opt,name=scrambled,proto3" json:"scrambled,omitempty"`
+}
+
+func (x *ScrambleWord) Reset() {
+	*x = ScrambleWord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScrambleWord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrambleWord) ProtoMessage() {}
+
+func (x *ScrambleWord) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrambleWord.ProtoReflect.Descriptor instead.
+func (*ScrambleWord) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ScrambleWord) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *ScrambleWord) GetHint() string {
+	if x != nil {
+		return x.Hint
+	}
+	return ""
+}
+
+func (x *ScrambleWord) GetScrambled() string {
+	if x != nil {
+		return x.Scrambled
+	}
+	return ""
+}
+
+type WordScrambleData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Words []*ScrambleWord `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+}
+
+func (x *WordScrambleData) Reset() {
+	*x = WordScrambleData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WordScrambleData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordScrambleData) ProtoMessage() {}
+
+func (x *WordScrambleData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordScrambleData.ProtoReflect.Descriptor instead.
+func (*WordScrambleData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WordScrambleData) GetWords() []*ScrambleWord {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+type CryptogramData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Quote        string            `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	Author       string            `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Alphabet     string            `protobuf:"bytes,3,opt,name=alphabet,proto3" json:"alphabet,omitempty"`
+	Ciphertext   string            `protobuf:"bytes,4,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	Solution     string            `protobuf:"bytes,5,opt,name=solution,proto3" json:"solution,omitempty"`
+	GivenLetters map[string]string `protobuf:"bytes,6,rep,name=given_letters,json=givenLetters,proto3" json:"given_letters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *CryptogramData) Reset() {
+	*x = CryptogramData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CryptogramData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CryptogramData) ProtoMessage() {}
+
+func (x *CryptogramData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CryptogramData.ProtoReflect.Descriptor instead.
+func (*CryptogramData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CryptogramData) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *CryptogramData) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *CryptogramData) GetAlphabet() string {
+	if x != nil {
+		return x.Alphabet
+	}
+	return ""
+}
+
+func (x *CryptogramData) GetCiphertext() string {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return ""
+}
+
+func (x *CryptogramData) GetSolution() string {
+	if x != nil {
+		return x.Solution
+	}
+	return ""
+}
+
+func (x *CryptogramData) GetGivenLetters() map[string]string {
+	if x != nil {
+		return x.GivenLetters
+	}
+	return nil
+}
+
+type WordLadderData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartWord string   `protobuf:"bytes,1,opt,name=start_word,json=startWord,proto3" json:"start_word,omitempty"`
+	EndWord   string   `protobuf:"bytes,2,opt,name=end_word,json=endWord,proto3" json:"end_word,omitempty"`
+	Ladder    []string `protobuf:"bytes,3,rep,name=ladder,proto3" json:"ladder,omitempty"`
+	Steps     int32    `protobuf:"varint,4,opt,name=steps,proto3" json:"steps,omitempty"`
+}
+
+func (x *WordLadderData) Reset() {
+	*x = WordLadderData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WordLadderData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordLadderData) ProtoMessage() {}
+
+func (x *WordLadderData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordLadderData.ProtoReflect.Descriptor instead.
+func (*WordLadderData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WordLadderData) GetStartWord() string {
+	if x != nil {
+		return x.StartWord
+	}
+	return ""
+}
+
+func (x *WordLadderData) GetEndWord() string {
+	if x != nil {
+		return x.EndWord
+	}
+	return ""
+}
+
+func (x *WordLadderData) GetLadder() []string {
+	if x != nil {
+		return x.Ladder
+	}
+	return nil
+}
+
+func (x *WordLadderData) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+type ConnectionsGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Label string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Level int32    `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Words []string `protobuf:"bytes,3,rep,name=words,proto3" json:"words,omitempty"`
+}
+
+func (x *ConnectionsGroup) Reset() {
+	*x = ConnectionsGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectionsGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionsGroup) ProtoMessage() {}
+
+func (x *ConnectionsGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionsGroup.ProtoReflect.Descriptor instead.
+func (*ConnectionsGroup) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ConnectionsGroup) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ConnectionsGroup) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *ConnectionsGroup) GetWords() []string {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+type ConnectionsData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Groups []*ConnectionsGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	Words  []string            `protobuf:"bytes,2,rep,name=words,proto3" json:"words,omitempty"`
+}
+
+func (x *ConnectionsData) Reset() {
+	*x = ConnectionsData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectionsData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionsData) ProtoMessage() {}
+
+func (x *ConnectionsData) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionsData.ProtoReflect.Descriptor instead.
+func (*ConnectionsData) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ConnectionsData) GetGroups() []*ConnectionsGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ConnectionsData) GetWords() []string {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+type Job struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status      JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=puzzle.v1.JobStatus" json:"status,omitempty"`
+	Request     *PuzzleRequest         `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	Puzzle      *Puzzle                `protobuf:"bytes,4,opt,name=puzzle,proto3" json:"puzzle,omitempty"` // preenchido quando status = JOB_STATUS_SUCCEEDED
+	Error       string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`   // preenchido quando status = JOB_STATUS_FAILED
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ErrorStatus int32                  `protobuf:"varint,8,opt,name=error_status,json=errorStatus,proto3" json:"error_status,omitempty"` // Status HTTP equivalente ao erro (ex: 422 para conteúdo bloqueado), quando status = JOB_STATUS_FAILED
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_puzzle_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_puzzle_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_puzzle_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *Job) GetRequest() *PuzzleRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *Job) GetPuzzle() *Puzzle {
+	if x != nil {
+		return x.Puzzle
+	}
+	return nil
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetErrorStatus() int32 {
+	if x != nil {
+		return x.ErrorStatus
+	}
+	return 0
+}
+
+var File_puzzle_proto protoreflect.FileDescriptor
+
+var file_puzzle_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa3, 0x01, 0x0a, 0x0d, 0x50,
+	0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09,
+	0x67, 0x61, 0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x67, 0x61, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x66,
+	0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64,
+	0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x6f, 0x70,
+	0x69, 0x63, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f, 0x70, 0x69, 0x63,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c,
+	0x22, 0x2f, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x49,
+	0x64, 0x22, 0x1f, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0xd6, 0x05, 0x0a, 0x06, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x61,
+	0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67,
+	0x61, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69,
+	0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x66,
+	0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x6f, 0x70, 0x69, 0x63,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x41, 0x0a, 0x0e, 0x63, 0x72, 0x6f, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x6f,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0d, 0x63, 0x72,
+	0x6f, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x45, 0x0a, 0x10, 0x77,
+	0x6f, 0x72, 0x64, 0x5f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x57, 0x6f, 0x72, 0x64, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x61, 0x74, 0x61,
+	0x48, 0x00, 0x52, 0x0e, 0x77, 0x6f, 0x72, 0x64, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x38, 0x0a, 0x0b, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00,
+	0x52, 0x0a, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x44, 0x61, 0x74, 0x61, 0x12, 0x32, 0x0a, 0x09,
+	0x71, 0x75, 0x69, 0x7a, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x69, 0x7a,
+	0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x71, 0x75, 0x69, 0x7a, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x4b, 0x0a, 0x12, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x73, 0x63, 0x72, 0x61, 0x6d, 0x62, 0x6c,
+	0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70,
+	0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x64, 0x53, 0x63, 0x72,
+	0x61, 0x6d, 0x62, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x10, 0x77, 0x6f, 0x72,
+	0x64, 0x53, 0x63, 0x72, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x44, 0x0a,
+	0x0f, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x5f, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x44, 0x61, 0x74,
+	0x61, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x45, 0x0a, 0x10, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x6c, 0x61, 0x64, 0x64,
+	0x65, 0x72, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x64, 0x4c, 0x61,
+	0x64, 0x64, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0e, 0x77, 0x6f, 0x72, 0x64,
+	0x4c, 0x61, 0x64, 0x64, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x47, 0x0a, 0x10, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44, 0x61, 0x74, 0x61,
+	0x48, 0x00, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44,
+	0x61, 0x74, 0x61, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x32, 0x0a, 0x08, 0x47,
+	0x72, 0x69, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x6f, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x6c, 0x73, 0x22,
+	0x8f, 0x01, 0x0a, 0x0d, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x57, 0x6f, 0x72,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6c, 0x75, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x5f, 0x72, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x52, 0x6f, 0x77, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x63, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x43, 0x6f, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x71, 0x0a, 0x0d, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x30, 0x0a, 0x09, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x72, 0x69, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x08, 0x67, 0x72, 0x69, 0x64,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x77,
+	0x6f, 0x72, 0x64, 0x73, 0x22, 0x66, 0x0a, 0x0e, 0x57, 0x6f, 0x72, 0x64, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x44, 0x61, 0x74, 0x61, 0x12, 0x30, 0x0a, 0x09, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x75, 0x7a, 0x7a,
+	0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x69, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x08,
+	0x67, 0x72, 0x69, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x77, 0x6f, 0x72, 0x64,
+	0x73, 0x5f, 0x74, 0x6f, 0x5f, 0x66, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x54, 0x6f, 0x46, 0x69, 0x6e, 0x64, 0x22, 0x21, 0x0a, 0x09,
+	0x53, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x52, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x65, 0x6c,
+	0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x22,
+	0xa4, 0x01, 0x0a, 0x0a, 0x53, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c,
+	0x0a, 0x06, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x64, 0x6f, 0x6b,
+	0x75, 0x52, 0x6f, 0x77, 0x52, 0x06, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x08,
+	0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x64, 0x6f, 0x6b,
+	0x75, 0x52, 0x6f, 0x77, 0x52, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x67, 0x69, 0x76, 0x65, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x67, 0x69, 0x76, 0x65, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x69,
+	0x71, 0x75, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x65, 0x63, 0x68,
+	0x6e, 0x69, 0x71, 0x75, 0x65, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x0c, 0x51, 0x75, 0x69, 0x7a, 0x51,
+	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a,
+	0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x41, 0x0a, 0x08, 0x51, 0x75, 0x69, 0x7a, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x35, 0x0a, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x51, 0x75, 0x69, 0x7a, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x54, 0x0a, 0x0c, 0x53, 0x63, 0x72, 0x61, 0x6d,
+	0x62, 0x6c, 0x65, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x68,
+	0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x69, 0x6e, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x73, 0x63, 0x72, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x63, 0x72, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x64, 0x22, 0x41, 0x0a,
+	0x10, 0x57, 0x6f, 0x72, 0x64, 0x53, 0x63, 0x72, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x2d, 0x0a, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x72,
+	0x61, 0x6d, 0x62, 0x6c, 0x65, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73,
+	0x22, 0xa9, 0x02, 0x0a, 0x0e, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x62, 0x65, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x62, 0x65, 0x74, 0x12, 0x1e, 0x0a,
+	0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x50, 0x0a, 0x0d, 0x67, 0x69, 0x76,
+	0x65, 0x6e, 0x5f, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2b, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x79,
+	0x70, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x47, 0x69, 0x76, 0x65,
+	0x6e, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x67,
+	0x69, 0x76, 0x65, 0x6e, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x1a, 0x3f, 0x0a, 0x11, 0x47,
+	0x69, 0x76, 0x65, 0x6e, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x78, 0x0a, 0x0e,
+	0x57, 0x6f, 0x72, 0x64, 0x4c, 0x61, 0x64, 0x64, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x65, 0x6e, 0x64, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x65, 0x6e, 0x64, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x64, 0x64,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x64, 0x64, 0x65, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x22, 0x54, 0x0a, 0x10, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x22, 0x5c, 0x0a, 0x0f,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x33, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x06, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x22, 0xd1, 0x02, 0x0a, 0x03, 0x4a,
+	0x6f, 0x62, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x14, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a,
+	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75,
+	0x7a, 0x7a, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x52, 0x06, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2a, 0x88,
+	0x01, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x16,
+	0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x4a, 0x4f, 0x42, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x12, 0x16, 0x0a, 0x12, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52,
+	0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x4a, 0x4f, 0x42, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44,
+	0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x32, 0xae, 0x02, 0x0a, 0x0d, 0x50, 0x75,
+	0x7a, 0x7a, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x0e, 0x47,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x18, 0x2e,
+	0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x47, 0x65,
+	0x74, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x1b, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x4a, 0x6f, 0x62, 0x12, 0x18, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
+	0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x12, 0x32,
+	0x0a, 0x06, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x12, 0x18, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a,
+	0x6f, 0x62, 0x12, 0x36, 0x0a, 0x08, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x12, 0x18,
+	0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f,
+	0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x75, 0x7a, 0x7a, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x30, 0x01, 0x42, 0x1b, 0x5a, 0x19, 0x70, 0x75,
+	0x7a, 0x7a, 0x6c, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x61, 0x70, 0x69, 0x2f, 0x70,
+	0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_puzzle_proto_rawDescOnce sync.Once
+	file_puzzle_proto_rawDescData = file_puzzle_proto_rawDesc
+)
+
+func file_puzzle_proto_rawDescGZIP() []byte {
+	file_puzzle_proto_rawDescOnce.Do(func() {
+		file_puzzle_proto_rawDescData = protoimpl.X.CompressGZIP(file_puzzle_proto_rawDescData)
+	})
+	return file_puzzle_proto_rawDescData
+}
+
+var file_puzzle_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_puzzle_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_puzzle_proto_goTypes = []any{
+	(JobStatus)(0),                // 0: puzzle.v1.JobStatus
+	(*PuzzleRequest)(nil),         // 1: puzzle.v1.PuzzleRequest
+	(*GetPuzzleRequest)(nil),      // 2: puzzle.v1.GetPuzzleRequest
+	(*GetJobRequest)(nil),         // 3: puzzle.v1.GetJobRequest
+	(*Puzzle)(nil),                // 4: puzzle.v1.Puzzle
+	(*GridSize)(nil),              // 5: puzzle.v1.GridSize
+	(*CrosswordWord)(nil),         // 6: puzzle.v1.CrosswordWord
+	(*CrosswordData)(nil),         // 7: puzzle.v1.CrosswordData
+	(*WordSearchData)(nil),        // 8: puzzle.v1.WordSearchData
+	(*SudokuRow)(nil),             // 9: puzzle.v1.SudokuRow
+	(*SudokuData)(nil),            // 10: puzzle.v1.SudokuData
+	(*QuizQuestion)(nil),          // 11: puzzle.v1.QuizQuestion
+	(*QuizData)(nil),              // 12: puzzle.v1.QuizData
+	(*ScrambleWord)(nil),          // 13: puzzle.v1.ScrambleWord
+	(*WordScrambleData)(nil),      // 14: puzzle.v1.WordScrambleData
+	(*CryptogramData)(nil),        // 15: puzzle.v1.CryptogramData
+	(*WordLadderData)(nil),        // 16: puzzle.v1.WordLadderData
+	(*ConnectionsGroup)(nil),      // 17: puzzle.v1.ConnectionsGroup
+	(*ConnectionsData)(nil),       // 18: puzzle.v1.ConnectionsData
+	(*Job)(nil),                   // 19: puzzle.v1.Job
+	nil,                           // 20: puzzle.v1.CryptogramData.GivenLettersEntry
+	(*timestamppb.Timestamp)(nil), // 21: google.protobuf.Timestamp
+}
+var file_puzzle_proto_depIdxs = []int32{
+	7,  // 0: puzzle.v1.Puzzle.crossword_data:type_name -> puzzle.v1.CrosswordData
+	8,  // 1: puzzle.v1.Puzzle.word_search_data:type_name -> puzzle.v1.WordSearchData
+	10, // 2: puzzle.v1.Puzzle.sudoku_data:type_name -> puzzle.v1.SudokuData
+	12, // 3: puzzle.v1.Puzzle.quiz_data:type_name -> puzzle.v1.QuizData
+	14, // 4: puzzle.v1.Puzzle.word_scramble_data:type_name -> puzzle.v1.WordScrambleData
+	15, // 5: puzzle.v1.Puzzle.cryptogram_data:type_name -> puzzle.v1.CryptogramData
+	16, // 6: puzzle.v1.Puzzle.word_ladder_data:type_name -> puzzle.v1.WordLadderData
+	18, // 7: puzzle.v1.Puzzle.connections_data:type_name -> puzzle.v1.ConnectionsData
+	5,  // 8: puzzle.v1.CrosswordData.grid_size:type_name -> puzzle.v1.GridSize
+	6,  // 9: puzzle.v1.CrosswordData.words:type_name -> puzzle.v1.CrosswordWord
+	5,  // 10: puzzle.v1.WordSearchData.grid_size:type_name -> puzzle.v1.GridSize
+	9,  // 11: puzzle.v1.SudokuData.puzzle:type_name -> puzzle.v1.SudokuRow
+	9,  // 12: puzzle.v1.SudokuData.solution:type_name -> puzzle.v1.SudokuRow
+	11, // 13: puzzle.v1.QuizData.questions:type_name -> puzzle.v1.QuizQuestion
+	13, // 14: puzzle.v1.WordScrambleData.words:type_name -> puzzle.v1.ScrambleWord
+	20, // 15: puzzle.v1.CryptogramData.given_letters:type_name -> puzzle.v1.CryptogramData.GivenLettersEntry
+	17, // 16: puzzle.v1.ConnectionsData.groups:type_name -> puzzle.v1.ConnectionsGroup
+	0,  // 17: puzzle.v1.Job.status:type_name -> puzzle.v1.JobStatus
+	1,  // 18: puzzle.v1.Job.request:type_name -> puzzle.v1.PuzzleRequest
+	4,  // 19: puzzle.v1.Job.puzzle:type_name -> puzzle.v1.Puzzle
+	21, // 20: puzzle.v1.Job.created_at:type_name -> google.protobuf.Timestamp
+	21, // 21: puzzle.v1.Job.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 22: puzzle.v1.PuzzleService.GeneratePuzzle:input_type -> puzzle.v1.PuzzleRequest
+	2,  // 23: puzzle.v1.PuzzleService.GetPuzzle:input_type -> puzzle.v1.GetPuzzleRequest
+	1,  // 24: puzzle.v1.PuzzleService.CreateJob:input_type -> puzzle.v1.PuzzleRequest
+	3,  // 25: puzzle.v1.PuzzleService.GetJob:input_type -> puzzle.v1.GetJobRequest
+	3,  // 26: puzzle.v1.PuzzleService.WatchJob:input_type -> puzzle.v1.GetJobRequest
+	4,  // 27: puzzle.v1.PuzzleService.GeneratePuzzle:output_type -> puzzle.v1.Puzzle
+	4,  // 28: puzzle.v1.PuzzleService.GetPuzzle:output_type -> puzzle.v1.Puzzle
+	19, // 29: puzzle.v1.PuzzleService.CreateJob:output_type -> puzzle.v1.Job
+	19, // 30: puzzle.v1.PuzzleService.GetJob:output_type -> puzzle.v1.Job
+	19, // 31: puzzle.v1.PuzzleService.WatchJob:output_type -> puzzle.v1.Job
+	27, // [27:32] is the sub-list for method output_type
+	22, // [22:27] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_puzzle_proto_init() }
+func file_puzzle_proto_init() {
+	if File_puzzle_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_puzzle_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*PuzzleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GetPuzzleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Puzzle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GridSize); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*CrosswordWord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*CrosswordData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*WordSearchData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*SudokuRow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*SudokuData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*QuizQuestion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*QuizData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*ScrambleWord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*WordScrambleData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*CryptogramData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*WordLadderData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*ConnectionsGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*ConnectionsData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_puzzle_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*Job); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_puzzle_proto_msgTypes[3].OneofWrappers = []any{
+		(*Puzzle_CrosswordData)(nil),
+		(*Puzzle_WordSearchData)(nil),
+		(*Puzzle_SudokuData)(nil),
+		(*Puzzle_QuizData)(nil),
+		(*Puzzle_WordScrambleData)(nil),
+		(*Puzzle_CryptogramData)(nil),
+		(*Puzzle_WordLadderData)(nil),
+		(*Puzzle_ConnectionsData)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_puzzle_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_puzzle_proto_goTypes,
+		DependencyIndexes: file_puzzle_proto_depIdxs,
+		EnumInfos:         file_puzzle_proto_enumTypes,
+		MessageInfos:      file_puzzle_proto_msgTypes,
+	}.Build()
+	File_puzzle_proto = out.File
+	file_puzzle_proto_rawDesc = nil
+	file_puzzle_proto_goTypes = nil
+	file_puzzle_proto_depIdxs = nil
+}
@@ -0,0 +1,292 @@
+// API gRPC do proxy de quebra-cabeças. As mensagens espelham PuzzleRequest e GeminiPuzzleResponse
+// (models.go); os nomes JSON dos campos são os mesmos da API HTTP.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: puzzle.proto
+
+package puzzlepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PuzzleService_GeneratePuzzle_FullMethodName = "/puzzle.v1.PuzzleService/GeneratePuzzle"
+	PuzzleService_GetPuzzle_FullMethodName      = "/puzzle.v1.PuzzleService/GetPuzzle"
+	PuzzleService_CreateJob_FullMethodName      = "/puzzle.v1.PuzzleService/CreateJob"
+	PuzzleService_GetJob_FullMethodName         = "/puzzle.v1.PuzzleService/GetJob"
+	PuzzleService_WatchJob_FullMethodName       = "/puzzle.v1.PuzzleService/WatchJob"
+)
+
+// PuzzleServiceClient is the client API for PuzzleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PuzzleServiceClient interface {
+	// Gera um quebra-cabeça ou retorna o que está em cache para os mesmos parâmetros.
+	GeneratePuzzle(ctx context.Context, in *PuzzleRequest, opts ...grpc.CallOption) (*Puzzle, error)
+	// Retorna um quebra-cabeça já gerado pelo seu ID público.
+	GetPuzzle(ctx context.Context, in *GetPuzzleRequest, opts ...grpc.CallOption) (*Puzzle, error)
+	// Cria um job de geração em segundo plano. Com callback_url, o evento do job é entregue como nos
+	// jobs da API HTTP (webhook assinado).
+	CreateJob(ctx context.Context, in *PuzzleRequest, opts ...grpc.CallOption) (*Job, error)
+	// Retorna o estado atual de um job.
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error)
+	// Envia o estado do job a cada mudança, até ele terminar.
+	WatchJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Job], error)
+}
+
+type puzzleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPuzzleServiceClient(cc grpc.ClientConnInterface) PuzzleServiceClient {
+	return &puzzleServiceClient{cc}
+}
+
+func (c *puzzleServiceClient) GeneratePuzzle(ctx context.Context, in *PuzzleRequest, opts ...grpc.CallOption) (*Puzzle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Puzzle)
+	err := c.cc.Invoke(ctx, PuzzleService_GeneratePuzzle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *puzzleServiceClient) GetPuzzle(ctx context.Context, in *GetPuzzleRequest, opts ...grpc.CallOption) (*Puzzle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Puzzle)
+	err := c.cc.Invoke(ctx, PuzzleService_GetPuzzle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *puzzleServiceClient) CreateJob(ctx context.Context, in *PuzzleRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, PuzzleService_CreateJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *puzzleServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, PuzzleService_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *puzzleServiceClient) WatchJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Job], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PuzzleService_ServiceDesc.Streams[0], PuzzleService_WatchJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetJobRequest, Job]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PuzzleService_WatchJobClient = grpc.ServerStreamingClient[Job]
+
+// PuzzleServiceServer is the server API for PuzzleService service.
+// All implementations must embed UnimplementedPuzzleServiceServer
+// for forward compatibility.
+type PuzzleServiceServer interface {
+	// Gera um quebra-cabeça ou retorna o que está em cache para os mesmos parâmetros.
+	GeneratePuzzle(context.Context, *PuzzleRequest) (*Puzzle, error)
+	// Retorna um quebra-cabeça já gerado pelo seu ID público.
+	GetPuzzle(context.Context, *GetPuzzleRequest) (*Puzzle, error)
+	// Cria um job de geração em segundo plano. Com callback_url, o evento do job é entregue como nos
+	// jobs da API HTTP (webhook assinado).
+	CreateJob(context.Context, *PuzzleRequest) (*Job, error)
+	// Retorna o estado atual de um job.
+	GetJob(context.Context, *GetJobRequest) (*Job, error)
+	// Envia o estado do job a cada mudança, até ele terminar.
+	WatchJob(*GetJobRequest, grpc.ServerStreamingServer[Job]) error
+	mustEmbedUnimplementedPuzzleServiceServer()
+}
+
+// UnimplementedPuzzleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPuzzleServiceServer struct{}
+
+func (UnimplementedPuzzleServiceServer) GeneratePuzzle(context.Context, *PuzzleRequest) (*Puzzle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePuzzle not implemented")
+}
+func (UnimplementedPuzzleServiceServer) GetPuzzle(context.Context, *GetPuzzleRequest) (*Puzzle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPuzzle not implemented")
+}
+func (UnimplementedPuzzleServiceServer) CreateJob(context.Context, *PuzzleRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateJob not implemented")
+}
+func (UnimplementedPuzzleServiceServer) GetJob(context.Context, *GetJobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedPuzzleServiceServer) WatchJob(*GetJobRequest, grpc.ServerStreamingServer[Job]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
+func (UnimplementedPuzzleServiceServer) mustEmbedUnimplementedPuzzleServiceServer() {}
+func (UnimplementedPuzzleServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafePuzzleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PuzzleServiceServer will
+// result in compilation errors.
+type UnsafePuzzleServiceServer interface {
+	mustEmbedUnimplementedPuzzleServiceServer()
+}
+
+func RegisterPuzzleServiceServer(s grpc.ServiceRegistrar, srv PuzzleServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPuzzleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PuzzleService_ServiceDesc, srv)
+}
+
+func _PuzzleService_GeneratePuzzle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PuzzleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PuzzleServiceServer).GeneratePuzzle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PuzzleService_GeneratePuzzle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PuzzleServiceServer).GeneratePuzzle(ctx, req.(*PuzzleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PuzzleService_GetPuzzle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPuzzleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PuzzleServiceServer).GetPuzzle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PuzzleService_GetPuzzle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PuzzleServiceServer).GetPuzzle(ctx, req.(*GetPuzzleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PuzzleService_CreateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PuzzleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PuzzleServiceServer).CreateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PuzzleService_CreateJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PuzzleServiceServer).CreateJob(ctx, req.(*PuzzleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PuzzleService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PuzzleServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PuzzleService_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PuzzleServiceServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PuzzleService_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PuzzleServiceServer).WatchJob(m, &grpc.GenericServerStream[GetJobRequest, Job]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PuzzleService_WatchJobServer = grpc.ServerStreamingServer[Job]
+
+// PuzzleService_ServiceDesc is the grpc.ServiceDesc for PuzzleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PuzzleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "puzzle.v1.PuzzleService",
+	HandlerType: (*PuzzleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GeneratePuzzle",
+			Handler:    _PuzzleService_GeneratePuzzle_Handler,
+		},
+		{
+			MethodName: "GetPuzzle",
+			Handler:    _PuzzleService_GetPuzzle_Handler,
+		},
+		{
+			MethodName: "CreateJob",
+			Handler:    _PuzzleService_CreateJob_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _PuzzleService_GetJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJob",
+			Handler:       _PuzzleService_WatchJob_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "puzzle.proto",
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// puzzleIDLength é o número de caracteres hexadecimais do hash da requisição usados como ID público.
+// 16 caracteres (64 bits) tornam colisões improváveis sem deixar os links de compartilhamento longos.
+const puzzleIDLength = 16
+
+// puzzleIDForHash deriva o ID público de um quebra-cabeça a partir do hash da requisição.
+// Por ser determinístico, o ID é estável: a mesma requisição sempre produz o mesmo ID,
+// inclusive para registros gravados antes da existência dos IDs.
+func puzzleIDForHash(requestHash string) string {
+	if len(requestHash) <= puzzleIDLength {
+		return requestHash
+	}
+	return requestHash[:puzzleIDLength]
+}
+
+// withResponseFields adiciona campos gerados pelo proxy (como "puzzleId") ao JSON do quebra-cabeça.
+// Se os dados não forem um objeto JSON, eles são retornados sem alteração.
+func withResponseFields(puzzleData []byte, extra map[string]interface{}) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(puzzleData, &fields); err != nil {
+		log.Printf("Não foi possível adicionar os campos %v à resposta: %v", extra, err)
+		return puzzleData
+	}
+	for name, value := range extra {
+		fields[name], _ = json.Marshal(value)
+	}
+	out, err := json.Marshal(fields)
+	if err != nil {
+		log.Printf("Não foi possível adicionar os campos %v à resposta: %v", extra, err)
+		return puzzleData
+	}
+	return out
+}
+
+// getPuzzleHandler é o manipulador de GET /puzzles/{id}. Ele retorna um quebra-cabeça já gerado
+// a partir do seu ID público, permitindo links de compartilhamento e referências em tickets de suporte.
+func (s *Server) getPuzzleHandler(w http.ResponseWriter, r *http.Request) {
+	puzzleID := r.PathValue("id")
+	entry, err := s.store.GetCachedPuzzleByID(puzzleID)
+	if err != nil {
+		log.Printf("Erro ao obter o quebra-cabeça %s: %v", puzzleID, err)
+		http.Error(w, "Erro interno do servidor: Falha ao obter o quebra-cabeça.", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(w, "Quebra-cabeça não encontrado.", http.StatusNotFound)
+		return
+	}
+
+	// Monta a resposta como POST /generate-puzzle e /daily, incluindo puzzleDate nos quebra-cabeças do dia.
+	puzzle := newGeneratedPuzzle(entry, true)
+	writeCacheable(w, r, puzzle.etag(), puzzle.Data)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"puzzle_proxy_api/puzzlepb"
+)
+
+func TestGetPuzzleByID(t *testing.T) {
+	s := &Server{
+		store: NewMemoryStore(),
+		daily: DailyConfig{Difficulty: "easy", Topics: []string{"numbers"}, Location: time.UTC},
+	}
+	handler := s.routes()
+	generated, err := s.generate(context.Background(), PuzzleRequest{GameType: "sudoku", Difficulty: "easy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily, err := s.dailyPuzzle(context.Background(), "sudoku", "en", "2026-10-18")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(id, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/puzzles/"+id, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, want := range []*generatedPuzzle{generated, daily} {
+		rec := get(want.PuzzleID, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /puzzles/%s: status %d: %s", want.PuzzleID, rec.Code, rec.Body)
+		}
+		// A resposta é a mesma de POST /generate-puzzle e de /daily, com puzzleId e, no do dia, puzzleDate.
+		if !sameJSON(t, rec.Body.Bytes(), want.Data) {
+			t.Errorf("GET /puzzles/%s = %s, quer %s", want.PuzzleID, rec.Body, want.Data)
+		}
+		etag := rec.Header().Get("ETag")
+		if etag != want.etag() {
+			t.Errorf("GET /puzzles/%s: ETag %q, quer %q", want.PuzzleID, etag, want.etag())
+		}
+		if rec := get(want.PuzzleID, etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("GET /puzzles/%s com If-None-Match: status %d com %d bytes, quer 304 sem corpo", want.PuzzleID, rec.Code, rec.Body.Len())
+		}
+	}
+
+	var fields struct {
+		PuzzleID   string `json:"puzzleId"`
+		PuzzleDate string `json:"puzzleDate"`
+	}
+	if err := json.Unmarshal(get(daily.PuzzleID, "").Body.Bytes(), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields.PuzzleID != daily.PuzzleID || fields.PuzzleDate != "2026-10-18" {
+		t.Errorf("quebra-cabeça do dia: puzzleId %q e puzzleDate %q, quer %q e 2026-10-18", fields.PuzzleID, fields.PuzzleDate, daily.PuzzleID)
+	}
+
+	if rec := get("desconhecido", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /puzzles/desconhecido: status %d, quer 404", rec.Code)
+	}
+
+	g := &puzzleGRPCServer{server: s}
+	pb, err := g.GetPuzzle(context.Background(), &puzzlepb.GetPuzzleRequest{PuzzleId: daily.PuzzleID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.GetPuzzleId() != daily.PuzzleID || pb.GetPuzzleDate() != "2026-10-18" || !pb.GetCached() {
+		t.Errorf("GetPuzzle = id %q, data %q, cached %v; quer %q, 2026-10-18, true", pb.GetPuzzleId(), pb.GetPuzzleDate(), pb.GetCached(), daily.PuzzleID)
+	}
+	if _, err := g.GetPuzzle(context.Background(), &puzzlepb.GetPuzzleRequest{PuzzleId: "desconhecido"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetPuzzle desconhecido = %v, quer codes.NotFound", err)
+	}
+}
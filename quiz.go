@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// O quiz é gerado pelo Gemini e verificado pelo servidor antes de ir para o cache.
+func init() {
+	RegisterGameType(GameTypeSpec[QuizData]{
+		Name:        "quiz",
+		Description: "trivia quiz",
+		DataField:   "quizData",
+		Prompt:      "quiz",
+		Validate: func(data *QuizData, _ PuzzleRequest) error {
+			return validateQuizData(data)
+		},
+	})
+}
+
+// validateQuizData verifica cada pergunta do quiz: enunciado preenchido, ao menos duas opções,
+// opções únicas (sem diferenciar maiúsculas e espaços) e correctIndex apontando para exatamente
+// uma delas. Como as opções são únicas, nenhuma outra opção pode repetir a resposta correta.
+func validateQuizData(data *QuizData) error {
+	if len(data.Questions) == 0 {
+		return fmt.Errorf("o quiz não tem perguntas")
+	}
+	for i, q := range data.Questions {
+		if strings.TrimSpace(q.Question) == "" {
+			return fmt.Errorf("pergunta %d: enunciado vazio", i+1)
+		}
+		if len(q.Options) < 2 {
+			return fmt.Errorf("pergunta %d: são necessárias ao menos 2 opções, recebidas %d", i+1, len(q.Options))
+		}
+		if q.CorrectIndex < 0 || q.CorrectIndex >= len(q.Options) {
+			return fmt.Errorf("pergunta %d: correctIndex %d fora do intervalo de %d opções", i+1, q.CorrectIndex, len(q.Options))
+		}
+
+		seen := make(map[string]int, len(q.Options))
+		for j, option := range q.Options {
+			key := normalizeQuizOption(option)
+			if key == "" {
+				return fmt.Errorf("pergunta %d: opção %d vazia", i+1, j+1)
+			}
+			if prev, ok := seen[key]; ok {
+				return fmt.Errorf("pergunta %d: opções %d e %d são iguais (%q)", i+1, prev+1, j+1, option)
+			}
+			seen[key] = j
+		}
+	}
+	return nil
+}
+
+// normalizeQuizOption normaliza uma opção para comparação: minúsculas e espaços colapsados.
+func normalizeQuizOption(option string) string {
+	return strings.Join(strings.Fields(strings.ToLower(option)), " ")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateQuizData(t *testing.T) {
+	question := func(options []string, correctIndex int) QuizQuestion {
+		return QuizQuestion{Question: "Qual é o maior planeta?", Options: options, CorrectIndex: correctIndex, Explanation: "É o maior."}
+	}
+	planets := []string{"Júpiter", "Saturno", "Marte", "Vênus"}
+
+	tests := []struct {
+		name      string
+		questions []QuizQuestion
+		problem   string // Trecho esperado no erro; vazio quando o quiz é válido
+	}{
+		{name: "válido", questions: []QuizQuestion{question(planets, 0), question(planets, 3)}},
+		{name: "duas opções", questions: []QuizQuestion{question([]string{"Sim", "Não"}, 1)}},
+		{name: "sem perguntas", problem: "não tem perguntas"},
+		{name: "enunciado vazio", questions: []QuizQuestion{{Question: "  ", Options: planets}}, problem: "enunciado vazio"},
+		{name: "uma opção", questions: []QuizQuestion{question([]string{"Júpiter"}, 0)}, problem: "ao menos 2 opções"},
+		{name: "sem opções", questions: []QuizQuestion{question(nil, 0)}, problem: "ao menos 2 opções"},
+		{name: "correctIndex negativo", questions: []QuizQuestion{question(planets, -1)}, problem: "correctIndex -1 fora do intervalo"},
+		{name: "correctIndex igual ao número de opções", questions: []QuizQuestion{question(planets, 4)}, problem: "correctIndex 4 fora do intervalo"},
+		{name: "opção repetida", questions: []QuizQuestion{question([]string{"Júpiter", "Marte", "Júpiter"}, 0)}, problem: "opções 1 e 3 são iguais"},
+		{name: "repetida com maiúsculas e espaços", questions: []QuizQuestion{question([]string{"Sistema  Solar", "Via Láctea", " sistema solar"}, 1)}, problem: "opções 1 e 3 são iguais"},
+		{name: "opção vazia", questions: []QuizQuestion{question([]string{"Júpiter", " "}, 0)}, problem: "opção 2 vazia"},
+		{name: "erro na segunda pergunta", questions: []QuizQuestion{question(planets, 0), question([]string{"Marte", "marte"}, 0)}, problem: "pergunta 2:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateQuizData(&QuizData{Questions: tt.questions})
+			if tt.problem == "" {
+				if err != nil {
+					t.Errorf("validateQuizData = %v, quer nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("validateQuizData = %v, quer erro com %q", err, tt.problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"expvar"
+	"net/http"
+	"reflect"
+)
+
+// apiRoute descreve um endpoint do proxy. A mesma tabela registra os manipuladores (routes) e gera a
+// especificação OpenAPI (buildOpenAPI), então um endpoint não pode existir sem estar documentado.
+type apiRoute struct {
+	Method  string           // Método HTTP
+	Path    string           // Caminho no formato do ServeMux, com parâmetros como {id}
+	Name    string           // operationId na especificação
+	Summary string           // Resumo exibido na documentação
+	Tag     string           // Agrupamento na documentação
+	Handler http.HandlerFunc // Manipulador
+	Admin   bool             // Exige ADMIN_TOKEN; só é registrado quando ele está configurado
+
+	Idempotent bool // Aceita o cabeçalho Idempotency-Key (ver withIdempotency)
+
+	Params   []apiParam   // Parâmetros de caminho e de query string
+	Request  reflect.Type // Corpo JSON da requisição (nil se não houver)
+	Response reflect.Type // Corpo JSON da resposta de sucesso (nil se não houver)
+	Status   int          // Status de sucesso; 0 significa 200
+	Errors   []apiError   // Respostas de erro possíveis (text/plain), e o 304 sem corpo dos endpoints com ETag
+
+	CacheControl string // Cache-Control padrão das respostas de sucesso; CACHE_CONTROL_<NAME> o substitui
+}
+
+// apiParam é um parâmetro de caminho ("path") ou da query string ("query"). Todos são strings.
+type apiParam struct {
+	Name, In, Description string
+	Required              bool
+}
+
+// apiError documenta um status de erro de um endpoint.
+type apiError struct {
+	Status      int
+	Description string
+}
+
+// generationErrors são os erros possíveis ao gerar um quebra-cabeça (ver generationErrorStatus).
+var generationErrors = []apiError{
+	{http.StatusUnprocessableEntity, "Conteúdo bloqueado pelo Gemini; tente outros tópicos"},
+	{http.StatusBadGateway, "Falha da API Gemini, resposta truncada ou quebra-cabeça inválido"},
+	{http.StatusServiceUnavailable, "Limite de uso da API Gemini atingido"},
+	{http.StatusInternalServerError, "Erro interno"},
+}
+
+// notModified é a resposta dos GETs com ETag quando o cliente já tem a versão atual (ver writeCacheable).
+var notModified = apiError{http.StatusNotModified, "Não modificado: o If-None-Match contém o ETag atual"}
+
+// idempotencyErrors são os erros de withIdempotency nos endpoints que aceitam Idempotency-Key.
+var idempotencyErrors = []apiError{
+	{http.StatusBadRequest, "Idempotency-Key com mais de 255 caracteres"},
+	{http.StatusUnprocessableEntity, "Idempotency-Key já usada com outro corpo"},
+}
+
+// apiErrors junta listas de erros em uma nova lista, sem alterar as listas compartilhadas.
+func apiErrors(lists ...[]apiError) []apiError {
+	var errs []apiError
+	for _, list := range lists {
+		errs = append(errs, list...)
+	}
+	return errs
+}
+
+// jobErrors são os erros possíveis ao criar um job (ver JobManager.Create).
+var jobErrors = []apiError{
+	{http.StatusBadRequest, "Payload inválido, gameType não suportado, parâmetros que o tipo de jogo não atende ou callbackUrl inválido"},
+	{http.StatusTooManyRequests, "Fila de jobs cheia (JOBS_MAX_PENDING); tente novamente mais tarde"},
+}
+
+// dailyParams são os parâmetros de GET /daily e GET /v1/daily.
+var dailyParams = []apiParam{
+	{Name: "gameType", In: "query", Description: "Tipo de jogo (padrão: o primeiro de DAILY_GAME_TYPES)"},
+	{Name: "language", In: "query", Description: "Idioma (padrão: o primeiro de DAILY_LANGUAGES)"},
+	{Name: "tz", In: "query", Description: "Fuso horário IANA usado para calcular a data (padrão: DAILY_TIMEZONE)"},
+}
+
+// cacheFilterParams são os filtros aceitos pelos endpoints administrativos de listagem e expurgo.
+var cacheFilterParams = []apiParam{
+	{Name: "gameType", In: "query", Description: "Tipo de jogo"},
+	{Name: "difficulty", In: "query", Description: "Dificuldade"},
+	{Name: "language", In: "query", Description: "Idioma"},
+	{Name: "topic", In: "query", Description: "Um dos tópicos da requisição"},
+	{Name: "from", In: "query", Description: "Criados a partir de (RFC 3339 ou AAAA-MM-DD)"},
+	{Name: "to", In: "query", Description: "Criados até (RFC 3339 ou AAAA-MM-DD, dia inteiro)"},
+}
+
+// adminPuzzleList é a resposta de GET /admin/puzzles.
+type adminPuzzleList struct {
+	Items  []CachedPuzzle `json:"items"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// adminWebhookDeliveryList é a resposta de GET /admin/webhook-deliveries.
+type adminWebhookDeliveryList struct {
+	Items  []WebhookDelivery `json:"items"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// adminPurgeResult é a resposta de DELETE /admin/puzzles.
+type adminPurgeResult struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// apiRoutes retorna a tabela de endpoints do servidor.
+func (s *Server) apiRoutes() []apiRoute {
+	return []apiRoute{
+		{
+			Method: "POST", Path: "/generate-puzzle", Name: "generatePuzzle", Tag: "puzzles",
+			Summary:    "Gera um quebra-cabeça ou retorna o que está em cache para os mesmos parâmetros (formato legado; ver POST /v1/puzzles)",
+			Handler:    s.generatePuzzleHandler,
+			Request:    reflect.TypeOf(PuzzleRequest{}),
+			Response:   reflect.TypeOf(GeminiPuzzleResponse{}),
+			Errors:     apiErrors([]apiError{{http.StatusBadRequest, "Payload inválido, gameType não suportado ou parâmetros que o tipo de jogo não atende"}}, generationErrors, idempotencyErrors),
+			Idempotent: true,
+		},
+		{
+			Method: "GET", Path: "/puzzles/{id}", Name: "getPuzzle", Tag: "puzzles",
+			Summary:  "Retorna um quebra-cabeça já gerado pelo seu ID público",
+			Handler:  s.getPuzzleHandler,
+			Params:   []apiParam{{Name: "id", In: "path", Description: "ID público (puzzleId)"}},
+			Response: reflect.TypeOf(GeminiPuzzleResponse{}),
+			Errors: []apiError{
+				notModified,
+				{http.StatusNotFound, "Quebra-cabeça não encontrado"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+			CacheControl: "public, max-age=86400",
+		},
+		{
+			Method: "GET", Path: "/daily", Name: "getDailyPuzzle", Tag: "puzzles",
+			Summary:      "Retorna o quebra-cabeça do dia, igual para todos os jogadores na mesma data",
+			Handler:      s.dailyHandler,
+			Params:       dailyParams,
+			Response:     reflect.TypeOf(GeminiPuzzleResponse{}),
+			Errors:       apiErrors([]apiError{notModified, {http.StatusBadRequest, "gameType, language ou tz inválido"}}, generationErrors),
+			CacheControl: "public, max-age=300",
+		},
+		{
+			Method: "POST", Path: "/jobs", Name: "createJob", Tag: "jobs",
+			Summary:      "Cria um job de geração em segundo plano; acompanhe em GET /jobs/{id}",
+			Handler:      s.createJobHandler,
+			Request:      reflect.TypeOf(JobRequest{}),
+			Response:     reflect.TypeOf(Job{}),
+			Status:       http.StatusAccepted,
+			Errors:       apiErrors(jobErrors, idempotencyErrors),
+			CacheControl: "no-store",
+			Idempotent:   true,
+		},
+		{
+			Method: "GET", Path: "/jobs/{id}", Name: "getJob", Tag: "jobs",
+			Summary:      "Retorna o estado de um job e, quando pronto, o quebra-cabeça",
+			Handler:      s.getJobHandler,
+			Params:       []apiParam{{Name: "id", In: "path", Description: "ID do job"}},
+			Response:     reflect.TypeOf(Job{}),
+			Errors:       []apiError{{http.StatusNotFound, "Job não encontrado ou expirado"}},
+			CacheControl: "no-store",
+		},
+		{
+			Method: "POST", Path: "/v1/puzzles", Name: "generatePuzzleV1", Tag: "v1",
+			Summary:    "Gera um quebra-cabeça ou retorna o que está em cache, com os metadados da geração",
+			Handler:    s.generatePuzzleV1Handler,
+			Request:    reflect.TypeOf(PuzzleRequest{}),
+			Response:   reflect.TypeOf(PuzzleEnvelope{}),
+			Errors:     apiErrors([]apiError{{http.StatusBadRequest, "Payload inválido, gameType não suportado ou parâmetros que o tipo de jogo não atende"}}, generationErrors, idempotencyErrors),
+			Idempotent: true,
+		},
+		{
+			Method: "GET", Path: "/v1/puzzles/{id}", Name: "getPuzzleV1", Tag: "v1",
+			Summary:  "Retorna um quebra-cabeça já gerado pelo seu ID público, com os metadados da geração",
+			Handler:  s.getPuzzleV1Handler,
+			Params:   []apiParam{{Name: "id", In: "path", Description: "ID público (puzzleId)"}},
+			Response: reflect.TypeOf(PuzzleEnvelope{}),
+			Errors: []apiError{
+				notModified,
+				{http.StatusNotFound, "Quebra-cabeça não encontrado"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+			CacheControl: "public, max-age=86400",
+		},
+		{
+			Method: "GET", Path: "/v1/daily", Name: "getDailyPuzzleV1", Tag: "v1",
+			Summary:      "Retorna o quebra-cabeça do dia, com os metadados da geração",
+			Handler:      s.dailyV1Handler,
+			Params:       dailyParams,
+			Response:     reflect.TypeOf(PuzzleEnvelope{}),
+			Errors:       apiErrors([]apiError{notModified, {http.StatusBadRequest, "gameType, language ou tz inválido"}}, generationErrors),
+			CacheControl: "public, max-age=300",
+		},
+		{
+			Method: "POST", Path: "/v1/jobs", Name: "createJobV1", Tag: "v1",
+			Summary:      "Cria um job de geração em segundo plano; acompanhe em GET /v1/jobs/{id}",
+			Handler:      s.createJobV1Handler,
+			Request:      reflect.TypeOf(JobRequest{}),
+			Response:     reflect.TypeOf(jobV1{}),
+			Status:       http.StatusAccepted,
+			Errors:       apiErrors(jobErrors, idempotencyErrors),
+			CacheControl: "no-store",
+			Idempotent:   true,
+		},
+		{
+			Method: "GET", Path: "/v1/jobs/{id}", Name: "getJobV1", Tag: "v1",
+			Summary:      "Retorna o estado de um job e, quando pronto, o quebra-cabeça com os metadados da geração",
+			Handler:      s.getJobV1Handler,
+			Params:       []apiParam{{Name: "id", In: "path", Description: "ID do job"}},
+			Response:     reflect.TypeOf(jobV1{}),
+			Errors:       []apiError{{http.StatusNotFound, "Job não encontrado ou expirado"}},
+			CacheControl: "no-store",
+		},
+		{
+			Method: "GET", Path: "/openapi.json", Name: "getOpenAPI", Tag: "meta",
+			Summary:      "Esta especificação OpenAPI",
+			Handler:      s.openAPIHandler,
+			Response:     reflect.TypeOf(map[string]interface{}{}),
+			Errors:       []apiError{notModified},
+			CacheControl: "public, max-age=3600",
+		},
+		{
+			Method: "GET", Path: "/admin/puzzles", Name: "adminListPuzzles", Tag: "admin", Admin: true,
+			Summary: "Lista os registros do cache, do mais recente para o mais antigo",
+			Handler: s.adminListPuzzlesHandler,
+			Params: append(cacheFilterParams,
+				apiParam{Name: "limit", In: "query", Description: "Máximo de registros (padrão 50, máximo 500)"},
+				apiParam{Name: "offset", In: "query", Description: "Registros a pular"},
+			),
+			Response: reflect.TypeOf(adminPuzzleList{}),
+			Errors: []apiError{
+				{http.StatusBadRequest, "Filtro inválido"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+		},
+		{
+			Method: "GET", Path: "/admin/puzzles/{hash}", Name: "adminGetPuzzle", Tag: "admin", Admin: true,
+			Summary:  "Retorna um registro do cache com request_params e response_data",
+			Handler:  s.adminGetPuzzleHandler,
+			Params:   []apiParam{{Name: "hash", In: "path", Description: "requestHash do registro"}},
+			Response: reflect.TypeOf(CachedPuzzle{}),
+			Errors: []apiError{
+				{http.StatusNotFound, "Registro não encontrado"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+		},
+		{
+			Method: "DELETE", Path: "/admin/puzzles/{hash}", Name: "adminDeletePuzzle", Tag: "admin", Admin: true,
+			Summary: "Remove um registro do cache",
+			Handler: s.adminDeletePuzzleHandler,
+			Params:  []apiParam{{Name: "hash", In: "path", Description: "requestHash do registro"}},
+			Status:  http.StatusNoContent,
+			Errors: []apiError{
+				{http.StatusNotFound, "Registro não encontrado"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+		},
+		{
+			Method: "DELETE", Path: "/admin/puzzles", Name: "adminPurgePuzzles", Tag: "admin", Admin: true,
+			Summary:  "Expurga os registros que atendem aos filtros (exige ao menos um filtro ou all=true)",
+			Handler:  s.adminPurgePuzzlesHandler,
+			Params:   append(cacheFilterParams, apiParam{Name: "all", In: "query", Description: "true para expurgar todo o cache sem filtros"}),
+			Response: reflect.TypeOf(adminPurgeResult{}),
+			Errors: []apiError{
+				{http.StatusBadRequest, "Filtro inválido ou ausente"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+		},
+		{
+			Method: "GET", Path: "/admin/webhook-deliveries", Name: "adminListWebhookDeliveries", Tag: "admin", Admin: true,
+			Summary: "Lista as tentativas de entrega dos webhooks dos jobs, da mais recente para a mais antiga",
+			Handler: s.adminListWebhookDeliveriesHandler,
+			Params: []apiParam{
+				{Name: "jobId", In: "query", Description: "ID do job"},
+				{Name: "limit", In: "query", Description: "Máximo de registros (padrão 50, máximo 500)"},
+				{Name: "offset", In: "query", Description: "Registros a pular"},
+			},
+			Response: reflect.TypeOf(adminWebhookDeliveryList{}),
+			Errors: []apiError{
+				{http.StatusBadRequest, "limit ou offset inválido"},
+				{http.StatusInternalServerError, "Erro interno"},
+			},
+		},
+		{
+			Method: "GET", Path: "/admin/metrics", Name: "adminMetrics", Tag: "admin", Admin: true,
+			Summary:  "Métricas do expvar (ex: gemini_finish_reasons, gemini_errors, webhook_deliveries)",
+			Handler:  expvar.Handler().ServeHTTP,
+			Response: reflect.TypeOf(map[string]interface{}{}),
+		},
+	}
+}
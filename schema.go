@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GeminiSchema é o subconjunto do objeto Schema da API Gemini usado nas respostas estruturadas.
+// É gerado a partir das structs de models.go por geminiSchemaFor, para que o modelo Go e o schema
+// enviado ao Gemini nunca divirjam.
+type GeminiSchema struct {
+	Type        string                   `json:"type"`                  // STRING, INTEGER, NUMBER, BOOLEAN, ARRAY ou OBJECT
+	Description string                   `json:"description,omitempty"` // Orientação para o modelo sobre o campo
+	Enum        []string                 `json:"enum,omitempty"`        // Valores permitidos (apenas STRING)
+	Items       *GeminiSchema            `json:"items,omitempty"`       // Schema dos itens (apenas ARRAY)
+	Properties  map[string]*GeminiSchema `json:"properties,omitempty"`  // Campos do objeto (apenas OBJECT)
+	Required    []string                 `json:"required,omitempty"`    // Campos obrigatórios (apenas OBJECT)
+}
+
+// geminiSchemaFor gera o schema de um tipo Go. Os campos de structs usam o nome da tag json e são
+// controlados pelas tags:
+//
+//	schema:"required"     o campo é obrigatório na resposta do Gemini
+//	schema:"-"            o campo não faz parte do schema (ex: dados calculados pelo proxy)
+//	enum:"across,down"    valores permitidos de um campo string
+//	description:"..."     descrição enviada ao Gemini
+//
+// Campos com json:"-" também são ignorados. Mapas, interfaces e outros tipos sem equivalente no schema
+// resultam em erro, a menos que estejam marcados com schema:"-".
+func geminiSchemaFor(t reflect.Type) (*GeminiSchema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &GeminiSchema{Type: "STRING"}, nil
+	case reflect.Bool:
+		return &GeminiSchema{Type: "BOOLEAN"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &GeminiSchema{Type: "INTEGER"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &GeminiSchema{Type: "NUMBER"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := geminiSchemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &GeminiSchema{Type: "ARRAY", Items: items}, nil
+	case reflect.Struct:
+		schema := &GeminiSchema{Type: "OBJECT", Properties: map[string]*GeminiSchema{}}
+		if err := addStructFields(schema, t); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("tipo %s não tem equivalente no schema do Gemini", t)
+	}
+}
+
+// addStructFields adiciona ao schema os campos exportados da struct, incluindo os de structs embutidas.
+func addStructFields(schema *GeminiSchema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" || field.Tag.Get("schema") == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructFields(schema, embedded); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := geminiSchemaFor(field.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		prop.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			if prop.Type != "STRING" {
+				return fmt.Errorf("%s.%s: enum só é permitido em campos string", t.Name(), field.Name)
+			}
+			prop.Enum = strings.Split(enum, ",")
+		}
+
+		schema.Properties[name] = prop
+		if field.Tag.Get("schema") == "required" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// Validate verifica um valor JSON decodificado genericamente (com json.Decoder.UseNumber) contra o schema
+// e retorna a lista de problemas encontrados, cada um prefixado pelo caminho do campo (ex: "$.quizData.questions[2].options").
+func (s *GeminiSchema) Validate(value interface{}) []string {
+	var problems []string
+	s.validate(value, "$", &problems)
+	return problems
+}
+
+// validate acumula em problems as divergências entre value e o schema no caminho informado.
+func (s *GeminiSchema) validate(value interface{}, path string, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch s.Type {
+	case "STRING":
+		str, ok := value.(string)
+		if !ok {
+			fail("esperado string, recebido %s", jsonKind(value))
+			return
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, str) {
+			fail("valor %q fora de %v", str, s.Enum)
+		}
+	case "INTEGER":
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("esperado inteiro, recebido %s", jsonKind(value))
+		} else if _, err := n.Int64(); err != nil {
+			fail("esperado inteiro, recebido %s", n)
+		}
+	case "NUMBER":
+		if _, ok := value.(json.Number); !ok {
+			fail("esperado número, recebido %s", jsonKind(value))
+		}
+	case "BOOLEAN":
+		if _, ok := value.(bool); !ok {
+			fail("esperado booleano, recebido %s", jsonKind(value))
+		}
+	case "ARRAY":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("esperado array, recebido %s", jsonKind(value))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case "OBJECT":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("esperado objeto, recebido %s", jsonKind(value))
+			return
+		}
+		for _, name := range s.Required {
+			if v, ok := obj[name]; !ok || v == nil {
+				fail("campo obrigatório %q ausente", name)
+			}
+		}
+		// Percorre os campos em ordem alfabética para que a lista de problemas seja estável.
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if v, ok := obj[name]; ok && v != nil {
+				s.Properties[name].validate(v, path+"."+name, problems)
+			}
+		}
+	}
+}
+
+// jsonKind descreve o tipo de um valor JSON decodificado, para as mensagens de validação.
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		return "número"
+	case bool:
+		return "booleano"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "objeto"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// containsString informa se a lista contém o valor.
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
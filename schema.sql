@@ -1,7 +1,58 @@
 CREATE TABLE IF NOT EXISTS cached_puzzles (
     id SERIAL PRIMARY KEY,
     request_hash TEXT UNIQUE NOT NULL,
+    puzzle_id TEXT UNIQUE,
+    puzzle_date DATE,
+    prompt_version TEXT,
+    model TEXT,
+    schema_version TEXT,
     request_params JSONB NOT NULL,
     response_data JSONB NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
+
+-- First response to each Idempotency-Key, replayed when a client retries the same request.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    operation TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    headers JSONB NOT NULL,
+    body BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (operation, idempotency_key)
+);
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys (created_at);
+
+-- One row per webhook delivery attempt (callbackUrl of POST /jobs and POST /v1/jobs).
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    delivery_id TEXT NOT NULL,
+    job_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    event TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_job_id_idx ON webhook_deliveries (job_id);
+
+-- Migrations for tables created by earlier versions. Safe to run more than once.
+
+-- Public puzzle IDs (GET /puzzles/{id}) are the first 16 hex characters of request_hash.
+ALTER TABLE cached_puzzles ADD COLUMN IF NOT EXISTS puzzle_id TEXT UNIQUE;
+UPDATE cached_puzzles SET puzzle_id = left(request_hash, 16) WHERE puzzle_id IS NULL;
+
+-- Daily puzzles (GET /daily) are regular cache rows tagged with the date they belong to.
+ALTER TABLE cached_puzzles ADD COLUMN IF NOT EXISTS puzzle_date DATE;
+
+-- Version of the prompt template that generated the puzzle; it is also part of the cache key.
+ALTER TABLE cached_puzzles ADD COLUMN IF NOT EXISTS prompt_version TEXT;
+
+-- Gemini model that generated the puzzle (the first model of the configured chain is part of the cache key).
+ALTER TABLE cached_puzzles ADD COLUMN IF NOT EXISTS model TEXT;
+
+-- Version of the game data format the puzzle was generated with (returned by the /v1 endpoints).
+ALTER TABLE cached_puzzles ADD COLUMN IF NOT EXISTS schema_version TEXT;
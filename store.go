@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Backends de cache suportados, selecionados pela variável CACHE_BACKEND.
+const (
+	CacheBackendPostgres = "postgres"
+	CacheBackendSQLite   = "sqlite"
+	CacheBackendMemory   = "memory"
+)
+
+// CacheStore define as operações de armazenamento usadas pelo servidor para o cache de quebra-cabeças.
+// Todas as implementações (PostgreSQL, SQLite e memória) devem se comportar da mesma forma:
+// um cache miss retorna (nil, nil) e salvar um hash existente substitui o registro anterior.
+type CacheStore interface {
+	// SaveCachedPuzzle insere ou atualiza o registro de puzzle.RequestHash. PuzzleID é definido pelo
+	// backend, assim como CreatedAt quando vier vazio.
+	SaveCachedPuzzle(puzzle *CachedPuzzle) error
+	// GetCachedPuzzleEntry retorna o registro completo do hash, ou nil se não houver registro.
+	GetCachedPuzzleEntry(requestHash string) (*CachedPuzzle, error)
+	// GetCachedPuzzleByID retorna o registro completo com o ID público informado, ou nil se não houver registro.
+	GetCachedPuzzleByID(puzzleID string) (*CachedPuzzle, error)
+	// ListCachedPuzzles lista os registros que atendem ao filtro, do mais recente para o mais antigo.
+	// ResponseData não é preenchido na listagem.
+	ListCachedPuzzles(filter CacheFilter) ([]CachedPuzzle, error)
+	// DeleteCachedPuzzle remove o registro do hash e informa se ele existia.
+	DeleteCachedPuzzle(requestHash string) (bool, error)
+	// PurgeCachedPuzzles remove todos os registros que atendem ao filtro (Limit e Offset são ignorados)
+	// e retorna quantos foram removidos.
+	PurgeCachedPuzzles(filter CacheFilter) (int64, error)
+	// GetIdempotentResponse retorna a resposta guardada para a Idempotency-Key do endpoint, ou nil se não houver.
+	GetIdempotentResponse(operation, key string) (*IdempotentResponse, error)
+	// SaveIdempotentResponse insere ou substitui a resposta de resp.Operation e resp.Key.
+	SaveIdempotentResponse(resp *IdempotentResponse) error
+	// PurgeIdempotentResponses remove as respostas guardadas antes de before e retorna quantas foram removidas.
+	PurgeIdempotentResponses(before time.Time) (int64, error)
+	// SaveWebhookDelivery registra uma tentativa de entrega de webhook. CreatedAt é definido pelo backend quando vier vazio.
+	SaveWebhookDelivery(delivery *WebhookDelivery) error
+	// ListWebhookDeliveries lista as tentativas de entrega que atendem ao filtro, da mais recente para a mais antiga.
+	ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error)
+	// Close libera os recursos do backend.
+	Close() error
+}
+
+// CachedPuzzle representa uma linha da tabela cached_puzzles.
+type CachedPuzzle struct {
+	RequestHash   string          `json:"requestHash"`             // Chave de cache (SHA256 da requisição)
+	PuzzleID      string          `json:"puzzleId"`                // ID público e estável do quebra-cabeça
+	PuzzleDate    string          `json:"puzzleDate,omitempty"`    // Data (AAAA-MM-DD) dos quebra-cabeças diários
+	PromptVersion string          `json:"promptVersion,omitempty"` // Versão do template de prompt usado (vazio em jogos gerados localmente)
+	Model         string          `json:"model,omitempty"`         // Modelo Gemini que gerou o quebra-cabeça (vazio em jogos gerados localmente)
+	SchemaVersion string          `json:"schemaVersion,omitempty"` // Versão do formato dos dados do jogo na geração (ver schemaVersionFor)
+	RequestParams json.RawMessage `json:"requestParams"`           // PuzzleRequest original em JSON
+	ResponseData  json.RawMessage `json:"responseData,omitempty"`  // Resposta do Gemini armazenada
+	CreatedAt     time.Time       `json:"createdAt"`               // Momento em que o registro foi salvo
+}
+
+// IdempotentResponse é a primeira resposta de um endpoint a uma Idempotency-Key, reenviada quando o
+// cliente repete a requisição com a mesma chave (ver withIdempotency).
+type IdempotentResponse struct {
+	Operation   string            // operationId do endpoint (ex: "generatePuzzle")
+	Key         string            // Valor do cabeçalho Idempotency-Key
+	RequestHash string            // SHA256 do corpo da requisição original
+	StatusCode  int               // Status HTTP da resposta
+	Header      map[string]string // Cabeçalhos reenviados (ex: Content-Type, Location, ETag)
+	Body        []byte            // Corpo da resposta
+	CreatedAt   time.Time         // Momento em que a resposta foi guardada
+}
+
+// CacheFilter seleciona registros do cache. Campos vazios ou zerados não filtram.
+// As comparações de texto não diferenciam maiúsculas de minúsculas.
+type CacheFilter struct {
+	GameType    string    // Valor de gameType na requisição
+	Difficulty  string    // Valor de difficulty na requisição
+	Language    string    // Valor de language na requisição
+	Topic       string    // Um dos tópicos da requisição
+	CreatedFrom time.Time // created_at >= CreatedFrom
+	CreatedTo   time.Time // created_at < CreatedTo
+	Limit       int       // Máximo de registros na listagem (0 usa o padrão)
+	Offset      int       // Registros a pular na listagem
+}
+
+// Limites de paginação das listagens administrativas (cache e entregas de webhooks).
+const (
+	defaultCacheListLimit = 50
+	maxCacheListLimit     = 500
+)
+
+// limit retorna o limite efetivo da listagem, aplicando o padrão e o máximo.
+func (f CacheFilter) limit() int {
+	return listLimit(f.Limit)
+}
+
+// listLimit aplica a um limite de listagem o padrão (quando zerado) e o máximo.
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return defaultCacheListLimit
+	}
+	if limit > maxCacheListLimit {
+		return maxCacheListLimit
+	}
+	return limit
+}
+
+// IsEmpty informa se o filtro não restringe nenhum campo.
+func (f CacheFilter) IsEmpty() bool {
+	return f.GameType == "" && f.Difficulty == "" && f.Language == "" && f.Topic == "" &&
+		f.CreatedFrom.IsZero() && f.CreatedTo.IsZero()
+}
+
+// NewCacheStore cria o backend de cache selecionado na configuração.
+func NewCacheStore(cfg *Config) (CacheStore, error) {
+	switch cfg.CacheBackend {
+	case CacheBackendPostgres:
+		return NewDBService(cfg.DatabaseURL)
+	case CacheBackendSQLite:
+		return NewSQLiteService(cfg.SQLitePath)
+	case CacheBackendMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("backend de cache desconhecido: %q", cfg.CacheBackend)
+	}
+}
+
+// createdAtOrNow retorna o momento de criação informado pelo chamador ou, se vazio, o momento atual,
+// sempre em UTC. O SQLite guarda o horário como texto: com fusos diferentes, a ordenação e os filtros
+// por data comparariam strings de fusos distintos.
+func createdAtOrNow(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now().UTC()
+	}
+	return t.UTC()
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testStores retorna os backends que devem passar na mesma suíte: memória, SQLite em memória e,
+// quando DATABASE_URL está definida, PostgreSQL (com schema.sql aplicado).
+func testStores(t *testing.T) map[string]func(t *testing.T) CacheStore {
+	stores := map[string]func(t *testing.T) CacheStore{
+		CacheBackendMemory: func(t *testing.T) CacheStore { return NewMemoryStore() },
+		CacheBackendSQLite: func(t *testing.T) CacheStore { return newTestSQLiteStore(t) },
+	}
+	if url := os.Getenv("DATABASE_URL"); url != "" {
+		stores[CacheBackendPostgres] = func(t *testing.T) CacheStore {
+			store, err := NewDBService(url)
+			if err != nil {
+				t.Fatalf("NewDBService: %v", err)
+			}
+			t.Cleanup(func() { store.Close() })
+			schema, err := os.ReadFile("schema.sql")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.db.Exec(string(schema)); err != nil {
+				t.Fatalf("aplicando schema.sql: %v", err)
+			}
+			return store
+		}
+	}
+	return stores
+}
+
+// testRun gera um prefixo aleatório para que os registros de uma execução não colidam com dados
+// existentes (relevante no PostgreSQL, que é persistente).
+func testRun(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// testPuzzle monta um registro com hash único cujo gameType identifica a execução do teste.
+func testPuzzle(run string, n int, req PuzzleRequest, createdAt time.Time) *CachedPuzzle {
+	params, _ := json.Marshal(req)
+	return &CachedPuzzle{
+		RequestHash:   fmt.Sprintf("%s%08d%s", run, n, "feedfacefeedfacefeedfacefeedface"),
+		PromptVersion: "v1",
+		Model:         "gemini-test",
+		SchemaVersion: "abc123",
+		RequestParams: params,
+		ResponseData:  json.RawMessage(fmt.Sprintf(`{"gameType": %q, "n": %d}`, req.GameType, n)),
+		CreatedAt:     createdAt,
+	}
+}
+
+// sameJSON compara dois documentos JSON ignorando espaços e ordem das chaves (o JSONB os normaliza).
+func sameJSON(t *testing.T, got, want []byte) bool {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("JSON inválido %q: %v", got, err)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("JSON inválido %q: %v", want, err)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+func hashes(entries []CachedPuzzle) []string {
+	out := []string{}
+	for _, e := range entries {
+		out = append(out, e.RequestHash)
+	}
+	return out
+}
+
+func TestCacheStoreConformance(t *testing.T) {
+	// Um fuso diferente de UTC garante que os backends normalizem os horários recebidos.
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	base := time.Date(2025, 7, 10, 9, 0, 0, 0, saoPaulo)
+
+	for name, open := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			store := open(t)
+			run := testRun(t)
+			gameType := "conformance-" + run // Isola os filtros e o expurgo dos dados existentes
+			t.Cleanup(func() { store.PurgeCachedPuzzles(CacheFilter{GameType: gameType}) })
+
+			t.Run("miss retorna nil sem erro", func(t *testing.T) {
+				entry, err := store.GetCachedPuzzleEntry(run + "-ausente")
+				if entry != nil || err != nil {
+					t.Errorf("GetCachedPuzzleEntry = %v, %v; quer nil, nil", entry, err)
+				}
+				entry, err = store.GetCachedPuzzleByID(run + "-ausente")
+				if entry != nil || err != nil {
+					t.Errorf("GetCachedPuzzleByID = %v, %v; quer nil, nil", entry, err)
+				}
+				resp, err := store.GetIdempotentResponse("generatePuzzle", run+"-ausente")
+				if resp != nil || err != nil {
+					t.Errorf("GetIdempotentResponse = %v, %v; quer nil, nil", resp, err)
+				}
+			})
+
+			t.Run("salva e lê o registro completo", func(t *testing.T) {
+				puzzle := testPuzzle(run, 1, PuzzleRequest{GameType: gameType, Difficulty: "easy", Topics: []string{"Animals"}, Language: "pt"}, base)
+				puzzle.PuzzleDate = "2025-07-10"
+				if err := store.SaveCachedPuzzle(puzzle); err != nil {
+					t.Fatalf("SaveCachedPuzzle: %v", err)
+				}
+				for _, get := range []func() (*CachedPuzzle, error){
+					func() (*CachedPuzzle, error) { return store.GetCachedPuzzleEntry(puzzle.RequestHash) },
+					func() (*CachedPuzzle, error) { return store.GetCachedPuzzleByID(puzzleIDForHash(puzzle.RequestHash)) },
+				} {
+					got, err := get()
+					if err != nil || got == nil {
+						t.Fatalf("leitura = %v, %v", got, err)
+					}
+					if got.RequestHash != puzzle.RequestHash || got.PuzzleID != puzzleIDForHash(puzzle.RequestHash) ||
+						got.PuzzleDate != "2025-07-10" || got.PromptVersion != "v1" || got.Model != "gemini-test" || got.SchemaVersion != "abc123" {
+						t.Errorf("registro = %+v", got)
+					}
+					if !got.CreatedAt.Equal(base) {
+						t.Errorf("CreatedAt = %v, quer %v", got.CreatedAt, base)
+					}
+					if !sameJSON(t, got.RequestParams, puzzle.RequestParams) || !sameJSON(t, got.ResponseData, puzzle.ResponseData) {
+						t.Errorf("JSON = %s / %s", got.RequestParams, got.ResponseData)
+					}
+				}
+			})
+
+			t.Run("CreatedAt vazio recebe o horário atual", func(t *testing.T) {
+				puzzle := testPuzzle(run, 2, PuzzleRequest{GameType: gameType}, time.Time{})
+				before := time.Now().Add(-time.Second)
+				if err := store.SaveCachedPuzzle(puzzle); err != nil {
+					t.Fatalf("SaveCachedPuzzle: %v", err)
+				}
+				got, err := store.GetCachedPuzzleEntry(puzzle.RequestHash)
+				if err != nil || got == nil {
+					t.Fatalf("GetCachedPuzzleEntry = %v, %v", got, err)
+				}
+				if got.CreatedAt.Before(before) {
+					t.Errorf("CreatedAt = %v, quer depois de %v", got.CreatedAt, before)
+				}
+				if _, err := store.DeleteCachedPuzzle(puzzle.RequestHash); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			t.Run("upsert substitui o registro", func(t *testing.T) {
+				puzzle := testPuzzle(run, 1, PuzzleRequest{GameType: gameType, Difficulty: "easy", Topics: []string{"Animals"}, Language: "pt"}, base)
+				puzzle.ResponseData = json.RawMessage(`{"replaced": true}`)
+				puzzle.Model = "gemini-other"
+				if err := store.SaveCachedPuzzle(puzzle); err != nil {
+					t.Fatalf("SaveCachedPuzzle: %v", err)
+				}
+				got, err := store.GetCachedPuzzleEntry(puzzle.RequestHash)
+				if err != nil || got == nil {
+					t.Fatalf("GetCachedPuzzleEntry = %v, %v", got, err)
+				}
+				if got.Model != "gemini-other" || !sameJSON(t, got.ResponseData, puzzle.ResponseData) || got.PuzzleDate != "" {
+					t.Errorf("registro após o upsert = %+v", got)
+				}
+				list, err := store.ListCachedPuzzles(CacheFilter{GameType: gameType})
+				if err != nil || len(list) != 1 {
+					t.Errorf("ListCachedPuzzles = %v, %v; quer 1 registro", hashes(list), err)
+				}
+			})
+
+			// Registros 1 (já salvo) a 5, um por hora a partir de base; o 5 é o mais recente.
+			requests := map[int]PuzzleRequest{
+				2: {GameType: gameType, Difficulty: "Hard", Topics: []string{"space", "Science"}, Language: "en"},
+				3: {GameType: gameType, Difficulty: "easy", Topics: []string{"science"}, Language: "EN"},
+				4: {GameType: gameType, Difficulty: "medium", Topics: []string{}, Language: "pt"},
+				5: {GameType: gameType, Difficulty: "hard", Topics: []string{"animals"}, Language: "es"},
+			}
+			all := map[int]string{1: testPuzzle(run, 1, PuzzleRequest{}, base).RequestHash}
+			for n := 2; n <= 5; n++ {
+				puzzle := testPuzzle(run, n, requests[n], base.Add(time.Duration(n-1)*time.Hour))
+				if err := store.SaveCachedPuzzle(puzzle); err != nil {
+					t.Fatalf("SaveCachedPuzzle(%d): %v", n, err)
+				}
+				all[n] = puzzle.RequestHash
+			}
+			want := func(ns ...int) []string {
+				out := []string{}
+				for _, n := range ns {
+					out = append(out, all[n])
+				}
+				return out
+			}
+
+			t.Run("filtros e paginação", func(t *testing.T) {
+				tests := []struct {
+					name   string
+					filter CacheFilter
+					want   []string
+				}{
+					{"todos, do mais recente", CacheFilter{}, want(5, 4, 3, 2, 1)},
+					{"difficulty sem diferenciar maiúsculas", CacheFilter{Difficulty: "HARD"}, want(5, 2)},
+					{"language", CacheFilter{Language: "en"}, want(3, 2)},
+					{"topic", CacheFilter{Topic: "science"}, want(3, 2)},
+					{"filtros combinados", CacheFilter{Topic: "animals", Language: "pt"}, want(1)},
+					{"CreatedFrom em outro fuso", CacheFilter{CreatedFrom: base.Add(3 * time.Hour).In(time.UTC)}, want(5, 4)},
+					{"CreatedTo exclusivo", CacheFilter{CreatedTo: base.Add(2 * time.Hour)}, want(2, 1)},
+					{"intervalo", CacheFilter{CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(3 * time.Hour)}, want(3, 2)},
+					{"limit", CacheFilter{Limit: 2}, want(5, 4)},
+					{"limit e offset", CacheFilter{Limit: 2, Offset: 1}, want(4, 3)},
+					{"offset além do fim", CacheFilter{Offset: 10}, want()},
+					{"sem resultados", CacheFilter{Language: "fr"}, want()},
+				}
+				for _, tt := range tests {
+					t.Run(tt.name, func(t *testing.T) {
+						tt.filter.GameType = gameType
+						got, err := store.ListCachedPuzzles(tt.filter)
+						if err != nil {
+							t.Fatalf("ListCachedPuzzles: %v", err)
+						}
+						if !reflect.DeepEqual(hashes(got), tt.want) {
+							t.Errorf("ListCachedPuzzles(%+v) = %v, quer %v", tt.filter, hashes(got), tt.want)
+						}
+						for _, entry := range got {
+							if entry.PuzzleID != puzzleIDForHash(entry.RequestHash) || entry.CreatedAt.IsZero() || len(entry.RequestParams) == 0 {
+								t.Errorf("registro listado incompleto: %+v", entry)
+							}
+						}
+					})
+				}
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				deleted, err := store.DeleteCachedPuzzle(all[4])
+				if err != nil || !deleted {
+					t.Fatalf("DeleteCachedPuzzle = %v, %v; quer true", deleted, err)
+				}
+				if entry, err := store.GetCachedPuzzleEntry(all[4]); entry != nil || err != nil {
+					t.Errorf("registro removido ainda existe: %v, %v", entry, err)
+				}
+				deleted, err = store.DeleteCachedPuzzle(all[4])
+				if err != nil || deleted {
+					t.Errorf("segundo DeleteCachedPuzzle = %v, %v; quer false", deleted, err)
+				}
+			})
+
+			t.Run("purge", func(t *testing.T) {
+				n, err := store.PurgeCachedPuzzles(CacheFilter{GameType: gameType, Difficulty: "hard", Limit: 1})
+				if err != nil || n != 2 {
+					t.Fatalf("PurgeCachedPuzzles = %d, %v; quer 2 (Limit é ignorado)", n, err)
+				}
+				got, err := store.ListCachedPuzzles(CacheFilter{GameType: gameType})
+				if err != nil || !reflect.DeepEqual(hashes(got), want(3, 1)) {
+					t.Errorf("restantes = %v, %v; quer %v", hashes(got), err, want(3, 1))
+				}
+				n, err = store.PurgeCachedPuzzles(CacheFilter{GameType: gameType})
+				if err != nil || n != 2 {
+					t.Errorf("PurgeCachedPuzzles = %d, %v; quer 2", n, err)
+				}
+			})
+
+			t.Run("respostas idempotentes", func(t *testing.T) {
+				resp := &IdempotentResponse{
+					Operation:   "createJob",
+					Key:         run + "-key",
+					RequestHash: "hash1",
+					StatusCode:  202,
+					Header:      map[string]string{"Location": "/jobs/1"},
+					Body:        []byte(`{"id":"1"}`),
+					CreatedAt:   base,
+				}
+				if err := store.SaveIdempotentResponse(resp); err != nil {
+					t.Fatalf("SaveIdempotentResponse: %v", err)
+				}
+				got, err := store.GetIdempotentResponse("createJob", resp.Key)
+				if err != nil || got == nil {
+					t.Fatalf("GetIdempotentResponse = %v, %v", got, err)
+				}
+				if got.RequestHash != "hash1" || got.StatusCode != 202 || !reflect.DeepEqual(got.Header, resp.Header) ||
+					string(got.Body) != string(resp.Body) || !got.CreatedAt.Equal(base) {
+					t.Errorf("resposta = %+v", got)
+				}
+				if other, err := store.GetIdempotentResponse("generatePuzzle", resp.Key); other != nil || err != nil {
+					t.Errorf("chave de outro endpoint = %v, %v; quer nil, nil", other, err)
+				}
+
+				resp.RequestHash, resp.CreatedAt = "hash2", base.Add(time.Hour)
+				if err := store.SaveIdempotentResponse(resp); err != nil {
+					t.Fatalf("SaveIdempotentResponse: %v", err)
+				}
+				if got, _ := store.GetIdempotentResponse("createJob", resp.Key); got == nil || got.RequestHash != "hash2" {
+					t.Errorf("upsert da resposta = %+v", got)
+				}
+
+				n, err := store.PurgeIdempotentResponses(base.Add(2 * time.Hour))
+				if err != nil || n < 1 {
+					t.Errorf("PurgeIdempotentResponses = %d, %v", n, err)
+				}
+				if got, err := store.GetIdempotentResponse("createJob", resp.Key); got != nil || err != nil {
+					t.Errorf("resposta expurgada = %v, %v", got, err)
+				}
+			})
+
+			t.Run("entregas de webhooks", func(t *testing.T) {
+				jobID := run + "-job"
+				for attempt := 1; attempt <= 3; attempt++ {
+					d := &WebhookDelivery{
+						DeliveryID: "d1", JobID: jobID, URL: "https://cms.example.com/hooks", Event: WebhookJobSucceeded,
+						Attempt: attempt, StatusCode: 500, Error: "status 500", DurationMS: 12,
+						CreatedAt: base.Add(time.Duration(attempt) * time.Minute),
+					}
+					if attempt == 3 {
+						d.StatusCode, d.Error = 0, "connection refused"
+					}
+					if err := store.SaveWebhookDelivery(d); err != nil {
+						t.Fatalf("SaveWebhookDelivery: %v", err)
+					}
+				}
+				if err := store.SaveWebhookDelivery(&WebhookDelivery{DeliveryID: "d2", JobID: run + "-outro", URL: "https://x", Event: WebhookJobFailed, Attempt: 1, StatusCode: 204}); err != nil {
+					t.Fatalf("SaveWebhookDelivery: %v", err)
+				}
+
+				got, err := store.ListWebhookDeliveries(WebhookDeliveryFilter{JobID: jobID})
+				if err != nil || len(got) != 3 {
+					t.Fatalf("ListWebhookDeliveries = %v, %v; quer 3", got, err)
+				}
+				if got[0].Attempt != 3 || got[0].StatusCode != 0 || got[0].Error != "connection refused" || !got[0].CreatedAt.Equal(base.Add(3*time.Minute)) {
+					t.Errorf("mais recente = %+v", got[0])
+				}
+				page, err := store.ListWebhookDeliveries(WebhookDeliveryFilter{JobID: jobID, Limit: 1, Offset: 1})
+				if err != nil || len(page) != 1 || page[0].Attempt != 2 || page[0].StatusCode != 500 || page[0].DurationMS != 12 {
+					t.Errorf("página = %+v, %v", page, err)
+				}
+			})
+		})
+	}
+}
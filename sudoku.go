@@ -0,0 +1,548 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+)
+
+// Sudoku é gerado inteiramente no proxy, sem chamar o Gemini: um algoritmo cria a grade completa,
+// remove pistas mantendo a solução única e classifica a dificuldade pelas técnicas que um jogador
+// precisa usar para resolvê-lo.
+
+func init() {
+	RegisterGameType(GameTypeSpec[SudokuData]{
+		Name:      "sudoku",
+		DataField: "sudokuData",
+		Generate:  GenerateSudokuPuzzle,
+	})
+}
+
+// Técnicas de resolução reconhecidas pelo classificador de dificuldade.
+const (
+	techNakedSingle  = "naked single"
+	techHiddenSingle = "hidden single"
+	techLockedCands  = "locked candidates"
+	techNakedPair    = "naked pair"
+	techHiddenPair   = "hidden pair"
+	techNakedTriple  = "naked triple"
+	techXWing        = "x-wing"
+)
+
+const (
+	sudokuMaxAttempts  = 3000  // Tentativas de gerar um sudoku da dificuldade pedida; "hard" acerta em ~0,7% delas
+	sudokuCellCount    = 81    // Células da grade 9x9
+	sudokuAllCandidate = 0x3FE // Máscara com os bits 1..9 ligados
+)
+
+// sudokuTechniqueLevel associa cada técnica ao nível de dificuldade que ela exige (1 = easy, 2 = medium, 3 = hard).
+var sudokuTechniqueLevel = map[string]int{
+	techNakedSingle:  1,
+	techHiddenSingle: 1,
+	techLockedCands:  2,
+	techNakedPair:    2,
+	techHiddenPair:   3,
+	techNakedTriple:  3,
+	techXWing:        3,
+}
+
+// sudokuDifficultyLevel converte a dificuldade da requisição no nível de técnicas e no número máximo de pistas.
+// Mais pistas deixam o quebra-cabeça mais fácil mesmo com as mesmas técnicas, então níveis mais baixos param antes.
+// GenerateSudokuPuzzle descarta as grades que terminam com mais pistas que maxGivens.
+var sudokuDifficultyLevel = map[string]struct{ level, maxGivens int }{
+	"easy":   {level: 1, maxGivens: 36},
+	"medium": {level: 2, maxGivens: 30},
+	"hard":   {level: 3, maxGivens: 26},
+}
+
+// sudokuUnits lista as 27 unidades (9 linhas, 9 colunas e 9 caixas) como índices de células.
+// sudokuPeers lista, para cada célula, as 20 células que compartilham uma unidade com ela.
+var sudokuUnits, sudokuPeers = buildSudokuUnits()
+
+// buildSudokuUnits calcula as unidades e os vizinhos de cada célula da grade 9x9.
+func buildSudokuUnits() ([27][9]int, [81][]int) {
+	var units [27][9]int
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			units[i][j] = i*9 + j                          // Linha i
+			units[9+i][j] = j*9 + i                        // Coluna i
+			units[18+i][j] = (i/3*3+j/3)*9 + (i%3*3 + j%3) // Caixa i
+		}
+	}
+
+	var peers [81][]int
+	for cell := 0; cell < sudokuCellCount; cell++ {
+		seen := map[int]bool{cell: true}
+		for _, unit := range units {
+			if !containsCell(unit[:], cell) {
+				continue
+			}
+			for _, peer := range unit {
+				if !seen[peer] {
+					seen[peer] = true
+					peers[cell] = append(peers[cell], peer)
+				}
+			}
+		}
+	}
+	return units, peers
+}
+
+// containsCell informa se a unidade contém a célula.
+func containsCell(unit []int, cell int) bool {
+	for _, c := range unit {
+		if c == cell {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateSudokuPuzzle gera um sudoku com solução única na dificuldade pedida e com no máximo maxGivens pistas.
+// A busca pode levar centenas de milissegundos no "hard"; ela para e retorna o erro de ctx quando a
+// requisição ou o job é cancelado.
+func GenerateSudokuPuzzle(ctx context.Context, req PuzzleRequest) (*SudokuData, error) {
+	difficulty := strings.ToLower(req.Difficulty)
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	target, ok := sudokuDifficultyLevel[difficulty]
+	if !ok {
+		return nil, fmt.Errorf("dificuldade de sudoku inválida %q: use easy, medium ou hard", req.Difficulty)
+	}
+
+	for attempt := 1; attempt <= sudokuMaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		solution := randomSudokuSolution()
+		puzzle, err := carveSudoku(ctx, solution, target.level, target.maxGivens)
+		if err != nil {
+			return nil, err
+		}
+
+		level, techniques, solved := gradeSudoku(puzzle)
+		if !solved || level != target.level {
+			continue // A remoção não atingiu a dificuldade pedida; tenta com outra grade.
+		}
+		givens := countGivens(puzzle)
+		if givens > target.maxGivens {
+			continue // As remoções possíveis acabaram antes de chegar ao número de pistas da dificuldade.
+		}
+
+		return &SudokuData{
+			Puzzle:     sudokuRows(puzzle),
+			Solution:   sudokuRows(solution),
+			Givens:     givens,
+			Techniques: techniques,
+		}, nil
+	}
+	return nil, fmt.Errorf("não foi possível gerar um sudoku %s após %d tentativas", difficulty, sudokuMaxAttempts)
+}
+
+// randomSudokuSolution gera uma grade completa e válida preenchendo as células com dígitos em ordem aleatória.
+func randomSudokuSolution() [81]int {
+	var grid [81]int
+	var fill func(cell int) bool
+	fill = func(cell int) bool {
+		if cell == sudokuCellCount {
+			return true
+		}
+		for _, digit := range rand.Perm(9) {
+			digit++
+			if sudokuCandidates(&grid, cell)&(1<<digit) == 0 {
+				continue
+			}
+			grid[cell] = digit
+			if fill(cell + 1) {
+				return true
+			}
+		}
+		grid[cell] = 0
+		return false
+	}
+	fill(0)
+	return grid
+}
+
+// carveSudoku remove pistas da solução em pares simétricos (rotação de 180°), desfazendo cada remoção
+// que quebre a unicidade da solução ou exija técnicas acima de targetLevel. Para quando restarem no máximo
+// maxGivens pistas e o quebra-cabeça já exigir targetLevel, ou quando nenhuma outra remoção for possível.
+// Retorna o erro de ctx se ele for cancelado no meio da remoção.
+func carveSudoku(ctx context.Context, solution [81]int, targetLevel, maxGivens int) ([81]int, error) {
+	puzzle := solution
+	givens, level := sudokuCellCount, 0
+	for _, cell := range rand.Perm(41) { // As células 0..40 representam todos os pares simétricos.
+		if givens <= maxGivens && level == targetLevel {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return puzzle, err
+		}
+
+		mirror := sudokuCellCount - 1 - cell
+		saved, savedMirror := puzzle[cell], puzzle[mirror]
+		puzzle[cell], puzzle[mirror] = 0, 0
+
+		if countSudokuSolutions(puzzle, 2) != 1 {
+			puzzle[cell], puzzle[mirror] = saved, savedMirror
+			continue
+		}
+		newLevel, _, solved := gradeSudoku(puzzle)
+		if !solved || newLevel > targetLevel {
+			puzzle[cell], puzzle[mirror] = saved, savedMirror
+			continue
+		}
+
+		level = newLevel
+		givens -= 2
+		if cell == mirror { // A célula central é o próprio par.
+			givens++
+		}
+	}
+	return puzzle, nil
+}
+
+// sudokuCandidates retorna a máscara de dígitos (bits 1..9) que podem ser colocados na célula.
+func sudokuCandidates(grid *[81]int, cell int) uint16 {
+	mask := uint16(sudokuAllCandidate)
+	for _, peer := range sudokuPeers[cell] {
+		mask &^= 1 << grid[peer]
+	}
+	return mask
+}
+
+// countSudokuSolutions conta as soluções da grade por busca em profundidade, parando em limit.
+// Sempre escolhe a célula vazia com menos candidatos, o que mantém a busca rápida.
+func countSudokuSolutions(grid [81]int, limit int) int {
+	best, bestCount := -1, 10
+	var bestMask uint16
+	for cell := 0; cell < sudokuCellCount; cell++ {
+		if grid[cell] != 0 {
+			continue
+		}
+		mask := sudokuCandidates(&grid, cell)
+		if n := bits.OnesCount16(mask); n < bestCount {
+			best, bestCount, bestMask = cell, n, mask
+			if n == 0 {
+				return 0
+			}
+		}
+	}
+	if best == -1 {
+		return 1 // Grade completa.
+	}
+
+	count := 0
+	for digit := 1; digit <= 9 && count < limit; digit++ {
+		if bestMask&(1<<digit) != 0 {
+			grid[best] = digit
+			count += countSudokuSolutions(grid, limit-count)
+		}
+	}
+	return count
+}
+
+// sudokuSolver resolve um sudoku apenas com técnicas lógicas, registrando quais foram necessárias.
+type sudokuSolver struct {
+	grid       [81]int
+	candidates [81]uint16
+	used       map[string]bool
+}
+
+// gradeSudoku resolve o sudoku com técnicas lógicas, da mais simples para a mais avançada.
+// Retorna o maior nível de técnica usado, as técnicas usadas (em ordem de nível) e se a resolução terminou.
+func gradeSudoku(puzzle [81]int) (int, []string, bool) {
+	s := &sudokuSolver{grid: puzzle, used: map[string]bool{}}
+	for cell := 0; cell < sudokuCellCount; cell++ {
+		if s.grid[cell] == 0 {
+			s.candidates[cell] = sudokuCandidates(&s.grid, cell)
+		}
+	}
+
+	// Cada passo aplica a técnica mais simples que fizer progresso e recomeça do início.
+	steps := []struct {
+		name  string
+		apply func() bool
+	}{
+		{techNakedSingle, s.nakedSingle},
+		{techHiddenSingle, s.hiddenSingle},
+		{techLockedCands, s.lockedCandidates},
+		{techNakedPair, s.nakedPair},
+		{techHiddenPair, s.hiddenPair},
+		{techNakedTriple, s.nakedTriple},
+		{techXWing, s.xWing},
+	}
+	for !s.solved() {
+		progress := false
+		for _, step := range steps {
+			if step.apply() {
+				s.used[step.name] = true
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+
+	level := 0
+	var techniques []string
+	for _, step := range steps {
+		if s.used[step.name] {
+			techniques = append(techniques, step.name)
+			level = max(level, sudokuTechniqueLevel[step.name])
+		}
+	}
+	return level, techniques, s.solved()
+}
+
+// solved informa se todas as células foram preenchidas.
+func (s *sudokuSolver) solved() bool {
+	for _, v := range s.grid {
+		if v == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// place coloca o dígito na célula e o remove dos candidatos dos vizinhos.
+func (s *sudokuSolver) place(cell, digit int) {
+	s.grid[cell] = digit
+	s.candidates[cell] = 0
+	for _, peer := range sudokuPeers[cell] {
+		s.candidates[peer] &^= 1 << digit
+	}
+}
+
+// eliminate remove os dígitos de mask dos candidatos da célula e informa se algo mudou.
+func (s *sudokuSolver) eliminate(cell int, mask uint16) bool {
+	if s.grid[cell] != 0 || s.candidates[cell]&mask == 0 {
+		return false
+	}
+	s.candidates[cell] &^= mask
+	return true
+}
+
+// nakedSingle: uma célula com um único candidato recebe esse dígito.
+func (s *sudokuSolver) nakedSingle() bool {
+	for cell := 0; cell < sudokuCellCount; cell++ {
+		if s.grid[cell] == 0 && bits.OnesCount16(s.candidates[cell]) == 1 {
+			s.place(cell, bits.TrailingZeros16(s.candidates[cell]))
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenSingle: um dígito que só cabe em uma célula de uma unidade vai para essa célula.
+func (s *sudokuSolver) hiddenSingle() bool {
+	for _, unit := range sudokuUnits {
+		for digit := 1; digit <= 9; digit++ {
+			cells := s.cellsWithCandidate(unit[:], digit)
+			if len(cells) == 1 {
+				s.place(cells[0], digit)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lockedCandidates: se um dígito de uma caixa só aparece em uma linha (ou coluna), ele sai do resto
+// dessa linha; e se o dígito de uma linha (ou coluna) só aparece em uma caixa, ele sai do resto da caixa.
+func (s *sudokuSolver) lockedCandidates() bool {
+	for digit := 1; digit <= 9; digit++ {
+		for _, unit := range sudokuUnits {
+			cells := s.cellsWithCandidate(unit[:], digit)
+			if len(cells) < 2 {
+				continue
+			}
+			for _, other := range sudokuUnits {
+				if other == unit || !allCellsIn(cells, other[:]) {
+					continue
+				}
+				changed := false
+				for _, cell := range other {
+					if !containsCell(unit[:], cell) && s.eliminate(cell, 1<<digit) {
+						changed = true
+					}
+				}
+				if changed {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// nakedPair: duas células de uma unidade com os mesmos dois candidatos os removem das demais células.
+func (s *sudokuSolver) nakedPair() bool {
+	for _, unit := range sudokuUnits {
+		for i, a := range unit {
+			if s.grid[a] != 0 || bits.OnesCount16(s.candidates[a]) != 2 {
+				continue
+			}
+			for _, b := range unit[i+1:] {
+				if s.grid[b] != 0 || s.candidates[b] != s.candidates[a] {
+					continue
+				}
+				if s.eliminateFromUnit(unit[:], s.candidates[a], a, b) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hiddenPair: dois dígitos que só aparecem nas mesmas duas células de uma unidade eliminam os
+// demais candidatos dessas células.
+func (s *sudokuSolver) hiddenPair() bool {
+	for _, unit := range sudokuUnits {
+		for d1 := 1; d1 <= 9; d1++ {
+			cells1 := s.cellsWithCandidate(unit[:], d1)
+			if len(cells1) != 2 {
+				continue
+			}
+			for d2 := d1 + 1; d2 <= 9; d2++ {
+				cells2 := s.cellsWithCandidate(unit[:], d2)
+				if len(cells2) != 2 || cells1[0] != cells2[0] || cells1[1] != cells2[1] {
+					continue
+				}
+				keep := uint16(1<<d1 | 1<<d2)
+				changed := s.eliminate(cells1[0], ^keep)
+				changed = s.eliminate(cells1[1], ^keep) || changed
+				if changed {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// nakedTriple: três células de uma unidade cujos candidatos somam só três dígitos os removem das demais.
+func (s *sudokuSolver) nakedTriple() bool {
+	for _, unit := range sudokuUnits {
+		var open []int
+		for _, cell := range unit {
+			if s.grid[cell] == 0 && bits.OnesCount16(s.candidates[cell]) <= 3 {
+				open = append(open, cell)
+			}
+		}
+		for i := 0; i < len(open); i++ {
+			for j := i + 1; j < len(open); j++ {
+				for k := j + 1; k < len(open); k++ {
+					mask := s.candidates[open[i]] | s.candidates[open[j]] | s.candidates[open[k]]
+					if bits.OnesCount16(mask) == 3 && s.eliminateFromUnit(unit[:], mask, open[i], open[j], open[k]) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// xWing: se um dígito aparece em exatamente as mesmas duas colunas em duas linhas, ele sai dessas colunas
+// nas demais linhas (e o mesmo com linhas e colunas trocadas).
+func (s *sudokuSolver) xWing() bool {
+	for digit := 1; digit <= 9; digit++ {
+		for _, base := range []int{0, 9} { // 0: linhas como base; 9: colunas como base
+			for a := 0; a < 9; a++ {
+				posA := s.positionsInUnit(sudokuUnits[base+a][:], digit)
+				if len(posA) != 2 {
+					continue
+				}
+				for b := a + 1; b < 9; b++ {
+					posB := s.positionsInUnit(sudokuUnits[base+b][:], digit)
+					if len(posB) != 2 || posA[0] != posB[0] || posA[1] != posB[1] {
+						continue
+					}
+					cover := 9 - base // As unidades cruzadas (colunas para linhas e vice-versa)
+					changed := false
+					for _, p := range posA {
+						for idx, cell := range sudokuUnits[cover+p] {
+							if idx != a && idx != b && s.eliminate(cell, 1<<digit) {
+								changed = true
+							}
+						}
+					}
+					if changed {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// cellsWithCandidate retorna as células vazias da unidade que têm o dígito como candidato.
+func (s *sudokuSolver) cellsWithCandidate(unit []int, digit int) []int {
+	var cells []int
+	for _, cell := range unit {
+		if s.grid[cell] == 0 && s.candidates[cell]&(1<<digit) != 0 {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// positionsInUnit retorna as posições (0..8) da unidade em que o dígito é candidato.
+func (s *sudokuSolver) positionsInUnit(unit []int, digit int) []int {
+	var positions []int
+	for pos, cell := range unit {
+		if s.grid[cell] == 0 && s.candidates[cell]&(1<<digit) != 0 {
+			positions = append(positions, pos)
+		}
+	}
+	return positions
+}
+
+// eliminateFromUnit remove mask de todas as células da unidade, exceto as informadas.
+func (s *sudokuSolver) eliminateFromUnit(unit []int, mask uint16, except ...int) bool {
+	changed := false
+	for _, cell := range unit {
+		if !containsCell(except, cell) && s.eliminate(cell, mask) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// allCellsIn informa se todas as células estão na unidade.
+func allCellsIn(cells, unit []int) bool {
+	for _, cell := range cells {
+		if !containsCell(unit, cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// sudokuRows converte a grade linear em 9 linhas de 9 dígitos (0 representa célula vazia).
+func sudokuRows(grid [81]int) [][]int {
+	rows := make([][]int, 9)
+	for r := range rows {
+		rows[r] = append([]int(nil), grid[r*9:r*9+9]...)
+	}
+	return rows
+}
+
+// countGivens conta as células preenchidas da grade.
+func countGivens(grid [81]int) int {
+	n := 0
+	for _, v := range grid {
+		if v != 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Cada dificuldade deve gerar sudokus com solução única, consistentes com a solução informada, no nível
+// de técnicas pedido e com no máximo maxGivens pistas.
+func TestGenerateSudokuPuzzle(t *testing.T) {
+	const puzzlesPerDifficulty = 3
+	for _, difficulty := range []string{"easy", "medium", "hard"} {
+		target := sudokuDifficultyLevel[difficulty]
+		for i := 0; i < puzzlesPerDifficulty; i++ {
+			data, err := GenerateSudokuPuzzle(context.Background(), PuzzleRequest{GameType: "sudoku", Difficulty: difficulty})
+			if err != nil {
+				t.Fatalf("%s: %v", difficulty, err)
+			}
+
+			var puzzle, solution [81]int
+			for r := 0; r < 9; r++ {
+				for c := 0; c < 9; c++ {
+					puzzle[r*9+c], solution[r*9+c] = data.Puzzle[r][c], data.Solution[r][c]
+					if puzzle[r*9+c] != 0 && puzzle[r*9+c] != solution[r*9+c] {
+						t.Errorf("%s: pista %d na linha %d, coluna %d difere da solução (%d)", difficulty, puzzle[r*9+c], r, c, solution[r*9+c])
+					}
+				}
+			}
+
+			if n := countSudokuSolutions(puzzle, 2); n != 1 {
+				t.Errorf("%s: %d soluções, quer 1", difficulty, n)
+			}
+			if givens := countGivens(puzzle); givens != data.Givens || givens > target.maxGivens {
+				t.Errorf("%s: %d pistas (givens = %d), quer no máximo %d", difficulty, givens, data.Givens, target.maxGivens)
+			}
+			if level, _, solved := gradeSudoku(puzzle); !solved || level != target.level {
+				t.Errorf("%s: nível %d (resolvido: %v), quer %d", difficulty, level, solved, target.level)
+			}
+		}
+	}
+}
+
+// Com o contexto cancelado, a geração para antes da primeira tentativa em vez de percorrer sudokuMaxAttempts.
+func TestGenerateSudokuPuzzleCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if data, err := GenerateSudokuPuzzle(ctx, PuzzleRequest{GameType: "sudoku", Difficulty: "hard"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateSudokuPuzzle = %v, %v; quer context.Canceled", data, err)
+	}
+	if _, err := carveSudoku(ctx, randomSudokuSolution(), 3, 26); !errors.Is(err, context.Canceled) {
+		t.Fatalf("carveSudoku = %v, quer context.Canceled", err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// UpstreamHTTPConfig controla o cliente HTTP usado nas chamadas à API Gemini.
+type UpstreamHTTPConfig struct {
+	Timeout               time.Duration // Tempo total de uma chamada, incluindo a leitura da resposta.
+	DialTimeout           time.Duration // Tempo para abrir a conexão TCP.
+	TLSHandshakeTimeout   time.Duration // Tempo para o handshake TLS.
+	ResponseHeaderTimeout time.Duration // Tempo entre o envio da requisição e os cabeçalhos da resposta.
+	IdleConnTimeout       time.Duration // Tempo que uma conexão ociosa fica no pool.
+	MaxIdleConnsPerHost   int           // Conexões ociosas mantidas por host.
+	ProxyURL              *url.URL      // Proxy de saída; se nil, usa HTTPS_PROXY/NO_PROXY do ambiente.
+
+	// Transport substitui o transporte padrão (ex: para interceptar o tráfego em testes).
+	// Se for definido, os timeouts de conexão e o proxy acima são ignorados; Timeout continua valendo.
+	Transport http.RoundTripper
+}
+
+// newUpstreamClient cria o cliente HTTP compartilhado pelas chamadas à API Gemini. O mesmo cliente é
+// reutilizado em todas as requisições, então as conexões (e as sessões TLS) ficam no pool entre chamadas.
+func newUpstreamClient(cfg UpstreamHTTPConfig) *http.Client {
+	transport := cfg.Transport
+	if transport == nil {
+		transport = newUpstreamTransport(cfg)
+	}
+	return &http.Client{Transport: transport, Timeout: cfg.Timeout}
+}
+
+// newUpstreamTransport cria o transporte com pool de conexões e os timeouts da configuração.
+func newUpstreamTransport(cfg UpstreamHTTPConfig) *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != nil {
+		proxy = http.ProxyURL(cfg.ProxyURL)
+	}
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          max(cfg.MaxIdleConnsPerHost, 100),
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
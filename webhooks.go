@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// webhookDeliveries conta as tentativas de entrega de webhooks por resultado.
+var webhookDeliveries = expvar.NewMap("webhook_deliveries") // "delivered", "retried" ou "failed"
+
+// Eventos enviados aos callbacks quando um job termina.
+const (
+	WebhookJobSucceeded = "job.succeeded"
+	WebhookJobFailed    = "job.failed"
+)
+
+// Cabeçalhos das entregas de webhooks.
+const (
+	webhookSignatureHeader = "X-Puzzle-Signature" // "t=<unix>,v1=<HMAC-SHA256 em hexadecimal de "<t>.<corpo>">"
+	webhookEventHeader     = "X-Puzzle-Event"     // Evento da entrega (ex: job.succeeded)
+	webhookDeliveryHeader  = "X-Puzzle-Delivery"  // ID da entrega, igual em todas as tentativas
+)
+
+// WebhookEvent é o corpo JSON enviado ao callbackUrl de um job.
+type WebhookEvent struct {
+	ID        string    `json:"id"`    // ID da entrega (o mesmo de X-Puzzle-Delivery), para o receptor descartar repetições
+	Event     string    `json:"event"` // WebhookJobSucceeded ou WebhookJobFailed
+	CreatedAt time.Time `json:"createdAt"`
+	Job       jobV1     `json:"job"` // O job no formato de GET /v1/jobs/{id}
+}
+
+// WebhookDelivery é uma tentativa de entrega de webhook, registrada na tabela webhook_deliveries.
+type WebhookDelivery struct {
+	DeliveryID string    `json:"deliveryId"`                                                                    // ID da entrega, igual em todas as tentativas
+	JobID      string    `json:"jobId"`                                                                         // Job que originou o evento
+	URL        string    `json:"url"`                                                                           // callbackUrl do job
+	Event      string    `json:"event" enum:"job.succeeded,job.failed"`                                         // Evento enviado
+	Attempt    int       `json:"attempt"`                                                                       // Número da tentativa, a partir de 1
+	StatusCode int       `json:"statusCode,omitempty" description:"HTTP status returned by the receiver"`       // 0 se não houve resposta
+	Error      string    `json:"error,omitempty" description:"Why the attempt failed; empty when it succeeded"` // Erro de rede ou status diferente de 2xx
+	DurationMS int64     `json:"durationMs"`                                                                    // Duração da tentativa em milissegundos
+	CreatedAt  time.Time `json:"createdAt"`                                                                     // Momento da tentativa
+}
+
+// WebhookDeliveryFilter seleciona tentativas de entrega. Campos vazios ou zerados não filtram.
+type WebhookDeliveryFilter struct {
+	JobID  string // Job que originou o evento
+	Limit  int    // Máximo de registros na listagem (0 usa o padrão)
+	Offset int    // Registros a pular na listagem
+}
+
+// limit retorna o limite efetivo da listagem, aplicando o padrão e o máximo.
+func (f WebhookDeliveryFilter) limit() int {
+	return listLimit(f.Limit)
+}
+
+// webhookSender entrega os eventos dos jobs aos callbacks, com novas tentativas e backoff exponencial.
+type webhookSender struct {
+	cfg    WebhookConfig
+	client *http.Client
+	store  CacheStore          // Registra cada tentativa (ver WebhookDelivery)
+	sleep  func(time.Duration) // Espera entre as tentativas (time.Sleep; substituída nos testes)
+}
+
+// newWebhookSender cria o entregador de webhooks. Redirecionamentos não são seguidos: o corpo assinado
+// seria reenviado (ou descartado, em 301/302) para um endereço que o cliente não informou. O proxy do
+// ambiente também não é usado, pois a checagem de endereços precisa ver o destino real da conexão.
+func newWebhookSender(cfg WebhookConfig, store CacheStore) *webhookSender {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		dialer.Control = blockInternalAddresses
+	}
+	return &webhookSender{
+		cfg:   cfg,
+		store: store,
+		sleep: time.Sleep,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, ForceAttemptHTTP2: true},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// validateCallbackURL verifica se o callbackUrl de um job pode ser usado. IPs internos e localhost são
+// recusados já aqui, a menos que WEBHOOK_ALLOW_PRIVATE esteja ativo; nomes de outros hosts só são
+// verificados na conexão (ver blockInternalAddresses), depois da resolução DNS.
+func (ws *webhookSender) validateCallbackURL(raw string) error {
+	if ws.cfg.Secret == "" {
+		return fmt.Errorf("callbackUrl exige WEBHOOK_SECRET configurado no servidor")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("callbackUrl inválido: %q (use uma URL http ou https absoluta)", raw)
+	}
+	if ws.cfg.AllowPrivate {
+		return nil
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); (ip != nil && isInternalIP(ip)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("callbackUrl aponta para um endereço interno: %q (defina WEBHOOK_ALLOW_PRIVATE=true para receptores locais)", raw)
+	}
+	return nil
+}
+
+// internalNetworks são as faixas recusadas como destino de webhooks, além das cobertas por isInternalIP:
+// "esta rede" (0.0.0.0/8) e o espaço compartilhado de CGNAT, que inclui o serviço de metadados da
+// Alibaba Cloud (100.100.100.200).
+var internalNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// isInternalIP informa se o IP é de loopback, de rede privada (incluindo fd00:ec2::254, os metadados
+// da AWS em IPv6), link-local (incluindo 169.254.169.254, os metadados da AWS, GCP e Azure), multicast
+// ou não especificado.
+func isInternalIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4 // Trata endereços IPv4 mapeados em IPv6 (::ffff:a.b.c.d) como IPv4.
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockInternalAddresses é o Control do net.Dialer dos webhooks: recusa a conexão quando o endereço já
+// resolvido é interno. Checar na conexão, e não na validação do callbackUrl, impede que um DNS que
+// muda de resposta (DNS rebinding) leve a entrega para a rede interna.
+func blockInternalAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("conexão de webhook bloqueada para o endereço interno %s", host)
+	}
+	return nil
+}
+
+// notify envia o evento do job terminado ao seu callbackUrl, se houver. As tentativas rodam em segundo
+// plano para não atrasar o próximo job.
+func (ws *webhookSender) notify(job Job) {
+	if job.CallbackURL == "" {
+		return
+	}
+	id, err := newJobID()
+	if err != nil {
+		log.Printf("Erro ao criar a entrega do webhook do job %s: %v", job.ID, err)
+		return
+	}
+	event := WebhookEvent{ID: id, Event: WebhookJobSucceeded, CreatedAt: time.Now().UTC(), Job: newJobV1(job)}
+	if job.Status == JobFailed {
+		event.Event = WebhookJobFailed
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Erro ao serializar o webhook do job %s: %v", job.ID, err)
+		return
+	}
+	go ws.deliver(event, job.CallbackURL, body)
+}
+
+// deliver envia o evento até o receptor responder 2xx ou as tentativas acabarem. O intervalo entre
+// as tentativas começa em InitialBackoff e dobra a cada falha.
+func (ws *webhookSender) deliver(event WebhookEvent, callbackURL string, body []byte) {
+	backoff := ws.cfg.InitialBackoff
+	for attempt := 1; attempt <= ws.cfg.MaxAttempts; attempt++ {
+		delivery := ws.attempt(event, callbackURL, body)
+		delivery.Attempt = attempt
+		if err := ws.store.SaveWebhookDelivery(&delivery); err != nil {
+			log.Printf("Erro ao registrar a entrega %s do webhook: %v", event.ID, err)
+		}
+
+		if delivery.Error == "" {
+			webhookDeliveries.Add("delivered", 1)
+			log.Printf("Webhook %s do job %s entregue em %s (tentativa %d)", event.Event, event.Job.ID, callbackURL, attempt)
+			return
+		}
+		if attempt == ws.cfg.MaxAttempts {
+			webhookDeliveries.Add("failed", 1)
+			log.Printf("Webhook %s do job %s não entregue em %s após %d tentativas: %s", event.Event, event.Job.ID, callbackURL, attempt, delivery.Error)
+			return
+		}
+		webhookDeliveries.Add("retried", 1)
+		log.Printf("Falha na tentativa %d do webhook %s do job %s (%s); nova tentativa em %s", attempt, event.Event, event.Job.ID, delivery.Error, backoff)
+		ws.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// attempt faz uma tentativa de entrega e retorna o seu registro (sem Attempt).
+func (ws *webhookSender) attempt(event WebhookEvent, callbackURL string, body []byte) (delivery WebhookDelivery) {
+	start := time.Now()
+	delivery = WebhookDelivery{
+		DeliveryID: event.ID,
+		JobID:      event.Job.ID,
+		URL:        callbackURL,
+		Event:      event.Event,
+		CreatedAt:  start.UTC(),
+	}
+	defer func() { delivery.DurationMS = time.Since(start).Milliseconds() }()
+
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "puzzle-proxy-webhooks")
+	req.Header.Set(webhookEventHeader, event.Event)
+	req.Header.Set(webhookDeliveryHeader, event.ID)
+	req.Header.Set(webhookSignatureHeader, signWebhook(ws.cfg.Secret, start.Unix(), body))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Permite reaproveitar a conexão.
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		delivery.Error = fmt.Sprintf("status %d", resp.StatusCode)
+	}
+	return delivery
+}
+
+// signWebhook calcula o valor de X-Puzzle-Signature: o HMAC-SHA256 de "<timestamp>.<corpo>" com o
+// segredo. O timestamp assinado permite ao receptor recusar entregas antigas reenviadas por terceiros.
+func signWebhook(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "s3cret"
+
+// webhookReceiver é um receptor local que verifica a assinatura de cada entrega e responde com os
+// status de statuses, na ordem (o último se repete).
+type webhookReceiver struct {
+	t        *testing.T
+	statuses []int
+
+	mu     sync.Mutex
+	events []WebhookEvent
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		rcv.t.Errorf("lendo o corpo: %v", err)
+	}
+	if !validWebhookSignature(r.Header.Get(webhookSignatureHeader), body) {
+		rcv.t.Errorf("assinatura inválida: %q", r.Header.Get(webhookSignatureHeader))
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		rcv.t.Errorf("corpo inválido: %v", err)
+	}
+	if got := r.Header.Get(webhookDeliveryHeader); got != event.ID {
+		rcv.t.Errorf("%s = %q, quer %q", webhookDeliveryHeader, got, event.ID)
+	}
+	if got := r.Header.Get(webhookEventHeader); got != event.Event {
+		rcv.t.Errorf("%s = %q, quer %q", webhookEventHeader, got, event.Event)
+	}
+
+	rcv.mu.Lock()
+	rcv.events = append(rcv.events, event)
+	n := len(rcv.events)
+	rcv.mu.Unlock()
+	w.WriteHeader(rcv.statuses[min(n, len(rcv.statuses))-1])
+}
+
+func (rcv *webhookReceiver) received() []WebhookEvent {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return append([]WebhookEvent(nil), rcv.events...)
+}
+
+// validWebhookSignature verifica X-Puzzle-Signature como um receptor faria.
+func validWebhookSignature(header string, body []byte) bool {
+	ts, v1, ok := strings.Cut(header, ",")
+	if !ok || !strings.HasPrefix(ts, "t=") || !strings.HasPrefix(v1, "v1=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(strings.TrimPrefix(ts, "t=") + "."))
+	mac.Write(body)
+	got, err := hex.DecodeString(strings.TrimPrefix(v1, "v1="))
+	return err == nil && hmac.Equal(got, mac.Sum(nil))
+}
+
+// newTestWebhookSender cria um entregador para o receptor local que registra as esperas em vez de dormir.
+func newTestWebhookSender(t *testing.T, store CacheStore, maxAttempts int) (*webhookSender, *[]time.Duration) {
+	t.Helper()
+	ws := newWebhookSender(WebhookConfig{
+		Secret:         testWebhookSecret,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Second,
+		Timeout:        5 * time.Second,
+		AllowPrivate:   true, // O httptest.Server escuta em 127.0.0.1.
+	}, store)
+	var sleeps []time.Duration
+	ws.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return ws, &sleeps
+}
+
+func newTestSQLiteStore(t *testing.T) *DBService {
+	t.Helper()
+	store, err := NewSQLiteService(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteService: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		wantCodes   []int // Status registrados em webhook_deliveries, da primeira à última tentativa
+		wantSleeps  []time.Duration
+	}{
+		{
+			name:        "sucesso na primeira tentativa",
+			statuses:    []int{http.StatusNoContent},
+			maxAttempts: 3,
+			wantCodes:   []int{http.StatusNoContent},
+		},
+		{
+			name:        "5xx e depois sucesso",
+			statuses:    []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK},
+			maxAttempts: 5,
+			wantCodes:   []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK},
+			wantSleeps:  []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:        "desiste depois de MaxAttempts",
+			statuses:    []int{http.StatusBadGateway},
+			maxAttempts: 4,
+			wantCodes:   []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantSleeps:  []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv := &webhookReceiver{t: t, statuses: tt.statuses}
+			srv := httptest.NewServer(rcv)
+			defer srv.Close()
+
+			store := newTestSQLiteStore(t)
+			ws, sleeps := newTestWebhookSender(t, store, tt.maxAttempts)
+			job := Job{ID: "job1", Status: JobSucceeded, PuzzleID: "abc", CallbackURL: srv.URL + "/hooks"}
+			event := WebhookEvent{ID: "delivery1", Event: WebhookJobSucceeded, CreatedAt: time.Now().UTC(), Job: newJobV1(job)}
+			body, _ := json.Marshal(event)
+			ws.deliver(event, job.CallbackURL, body)
+
+			if got := len(rcv.received()); got != len(tt.wantCodes) {
+				t.Fatalf("receptor recebeu %d requisições, quer %d", got, len(tt.wantCodes))
+			}
+			if !reflect.DeepEqual(*sleeps, tt.wantSleeps) {
+				t.Errorf("esperas = %v, quer %v", *sleeps, tt.wantSleeps)
+			}
+
+			rows, err := store.ListWebhookDeliveries(WebhookDeliveryFilter{JobID: "job1"})
+			if err != nil {
+				t.Fatalf("ListWebhookDeliveries: %v", err)
+			}
+			if len(rows) != len(tt.wantCodes) {
+				t.Fatalf("%d linhas em webhook_deliveries, quer %d", len(rows), len(tt.wantCodes))
+			}
+			for i, row := range rows {
+				attempt := len(rows) - i // Listagem da mais recente para a mais antiga
+				want := tt.wantCodes[attempt-1]
+				if row.Attempt != attempt || row.StatusCode != want || row.DeliveryID != "delivery1" ||
+					row.URL != job.CallbackURL || row.Event != WebhookJobSucceeded {
+					t.Errorf("linha %d = %+v, quer tentativa %d com status %d", i, row, attempt, want)
+				}
+				if success := want >= 200 && want < 300; success != (row.Error == "") {
+					t.Errorf("tentativa %d: Error = %q com status %d", attempt, row.Error, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookBlocksInternalAddressesOnDial(t *testing.T) {
+	rcv := &webhookReceiver{t: t, statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	ws := newWebhookSender(WebhookConfig{Secret: testWebhookSecret, MaxAttempts: 1, Timeout: 5 * time.Second}, store)
+	// A entrega não passa por validateCallbackURL: a conexão deve ser recusada depois da resolução DNS,
+	// tanto para o IP literal quanto para um nome que resolve para ele.
+	for _, target := range []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)} {
+		event := WebhookEvent{ID: "d", Event: WebhookJobFailed, Job: newJobV1(Job{ID: "job1"})}
+		delivery := ws.attempt(event, target, []byte("{}"))
+		if !strings.Contains(delivery.Error, "bloqueada") {
+			t.Errorf("entrega para %s: Error = %q, quer conexão bloqueada", target, delivery.Error)
+		}
+	}
+	if n := len(rcv.received()); n != 0 {
+		t.Errorf("o receptor interno recebeu %d requisições", n)
+	}
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		secret       string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		{url: "https://cms.example.com/hooks", secret: "x"},
+		{url: "http://cms.example.com:8080/hooks", secret: "x"},
+		{url: "https://cms.example.com/hooks", wantErr: true}, // Sem WEBHOOK_SECRET
+		{url: "ftp://cms.example.com/hooks", secret: "x", wantErr: true},
+		{url: "/hooks", secret: "x", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data", secret: "x", wantErr: true},
+		{url: "http://127.0.0.1:9000/", secret: "x", wantErr: true},
+		{url: "http://localhost:9000/", secret: "x", wantErr: true},
+		{url: "http://app.localhost/", secret: "x", wantErr: true},
+		{url: "http://10.0.0.5/", secret: "x", wantErr: true},
+		{url: "http://192.168.1.10/", secret: "x", wantErr: true},
+		{url: "http://100.100.100.200/", secret: "x", wantErr: true},
+		{url: "http://0.0.0.0:8080/", secret: "x", wantErr: true},
+		{url: "http://[::1]:9000/", secret: "x", wantErr: true},
+		{url: "http://[::ffff:169.254.169.254]/", secret: "x", wantErr: true},
+		{url: "http://[fd00:ec2::254]/", secret: "x", wantErr: true},
+		{url: "http://localhost:9000/", secret: "x", allowPrivate: true},
+		{url: "http://10.0.0.5/", secret: "x", allowPrivate: true},
+	}
+	for _, tt := range tests {
+		ws := newWebhookSender(WebhookConfig{Secret: tt.secret, AllowPrivate: tt.allowPrivate}, NewMemoryStore())
+		if err := ws.validateCallbackURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("validateCallbackURL(%q, allowPrivate=%v) = %v, quer erro: %v", tt.url, tt.allowPrivate, err, tt.wantErr)
+		}
+	}
+}
+
+func TestJobCallback(t *testing.T) {
+	rcv := &webhookReceiver{t: t, statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	store := newTestSQLiteStore(t)
+	s := &Server{store: store}
+	done := make(chan struct{})
+	s.jobs = NewJobManager(JobsConfig{MaxConcurrent: 1, Retention: time.Hour}, func(context.Context, PuzzleRequest) (*generatedPuzzle, error) {
+		entry := &CachedPuzzle{RequestHash: "0123456789abcdef0123", ResponseData: json.RawMessage(`{"gameType":"crossword"}`), CreatedAt: time.Now().UTC()}
+		entry.PuzzleID = puzzleIDForHash(entry.RequestHash)
+		return newGeneratedPuzzle(entry, false), nil
+	}, func(job Job) {
+		s.webhooks.notify(job)
+		close(done)
+	})
+	handler := s.routes()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(body)))
+		return rr
+	}
+	// Sem WEBHOOK_ALLOW_PRIVATE, endereços internos são recusados na criação do job.
+	s.webhooks = newWebhookSender(WebhookConfig{Secret: testWebhookSecret, MaxAttempts: 1}, store)
+	for _, callbackURL := range []string{"http://169.254.169.254/latest", srv.URL} {
+		if rr := post(`{"gameType":"crossword","callbackUrl":"` + callbackURL + `"}`); rr.Code != http.StatusBadRequest {
+			t.Errorf("callbackUrl %s: status %d, quer 400", callbackURL, rr.Code)
+		}
+	}
+
+	s.webhooks, _ = newTestWebhookSender(t, store, 1)
+	if rr := post(`{"gameType":"crossword","difficulty":"easy","language":"en","callbackUrl":"` + srv.URL + `"}`); rr.Code != http.StatusAccepted {
+		t.Fatalf("POST /v1/jobs: status %d: %s", rr.Code, rr.Body)
+	}
+
+	<-done
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rcv.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	events := rcv.received()
+	if len(events) != 1 {
+		t.Fatalf("receptor recebeu %d eventos, quer 1", len(events))
+	}
+	if ev := events[0]; ev.Event != WebhookJobSucceeded || ev.Job.Status != JobSucceeded || ev.Job.Puzzle == nil {
+		t.Errorf("evento = %+v", ev)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"sync"
+)
+
+// O Gemini sugere palavras dos tópicos; o proxy escolhe as pontas e calcula os degraus.
+func init() {
+	RegisterGameType(GameTypeSpec[WordLadderData]{
+		Name:        "wordladder",
+		Description: "word ladder puzzle",
+		DataField:   "wordLadderData",
+		Prompt:      "wordladder",
+		CheckRequest: func(req PuzzleRequest) error {
+			_, err := wordListFor(req.Language)
+			return err
+		},
+		PostProcess: func(data *WordLadderData, req PuzzleRequest) error {
+			return buildWordLadder(data, req.Language, strings.ToLower(req.Difficulty))
+		},
+	})
+}
+
+// wordListFS contém as listas de palavras da escada, uma por idioma (wordlists/<código>.txt).
+// As listas são embutidas no binário para que os degraus sejam verificados sem dependências externas.
+//
+//go:embed wordlists/*.txt
+var wordListFS embed.FS
+
+// wordLadderStepRange define o intervalo de passos (trocas de letra) aceito em cada dificuldade.
+var wordLadderStepRange = map[string][2]int{
+	"easy":   {3, 4},
+	"medium": {5, 6},
+	"hard":   {7, 10},
+}
+
+// wordLists guarda as listas já carregadas, indexadas pelo código do idioma. Cada lista é lida do
+// binário apenas uma vez, na primeira escada do idioma.
+var wordLists struct {
+	mu    sync.Mutex
+	words map[string]map[string]bool
+}
+
+// wordListFor retorna o conjunto de palavras (em maiúsculas) do idioma, ou erro se não houver lista.
+func wordListFor(language string) (map[string]bool, error) {
+	code := languageCode(language)
+
+	wordLists.mu.Lock()
+	defer wordLists.mu.Unlock()
+	if words, ok := wordLists.words[code]; ok {
+		return words, nil
+	}
+
+	data, err := wordListFS.ReadFile("wordlists/" + code + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("não há lista de palavras para o idioma %q", language)
+	}
+	words := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words[strings.ToUpper(line)] = true
+	}
+
+	if wordLists.words == nil {
+		wordLists.words = map[string]map[string]bool{}
+	}
+	wordLists.words[code] = words
+	return words, nil
+}
+
+// buildWordLadder escolhe as palavras inicial e final entre as candidatas do Gemini e calcula os degraus
+// intermediários por busca em largura sobre a lista de palavras do idioma, de modo que cada degrau seja
+// uma palavra do dicionário que difere da anterior em exatamente uma letra.
+//
+// Sempre que possível as duas pontas são candidatas (ou seja, relacionadas aos tópicos). Se nenhum par
+// de candidatas estiver a uma distância adequada à dificuldade, a palavra final é qualquer palavra do
+// dicionário a essa distância de uma candidata.
+//
+// Os problemas com as candidatas do Gemini são retornados como *PuzzleValidationError, para que o prompt
+// de reparo peça outras palavras; a falta da lista do idioma é recusada antes (ver CheckRequest).
+func buildWordLadder(data *WordLadderData, language, difficulty string) error {
+	words, err := wordListFor(language)
+	if err != nil {
+		return err
+	}
+	stepRange, ok := wordLadderStepRange[difficulty]
+	if !ok {
+		stepRange = wordLadderStepRange["medium"]
+	}
+
+	// Candidatas que existem no dicionário, sem repetições e em ordem aleatória.
+	var candidates []string
+	seen := map[string]bool{}
+	for _, c := range data.CandidateWords {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		if words[c] && !seen[c] {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return invalidPuzzle(fmt.Errorf("nenhuma palavra candidata está na lista de palavras do idioma %q", language))
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+	inRange := func(steps int) bool { return steps >= stepRange[0] && steps <= stepRange[1] }
+
+	// Primeiro tenta um par de candidatas; depois, uma candidata e qualquer palavra do dicionário.
+	for _, topicEnd := range []bool{true, false} {
+		for _, start := range candidates {
+			parents := wordLadderSearch(start, words)
+
+			var ends []string
+			if topicEnd {
+				ends = candidates
+			} else {
+				for w := range parents {
+					ends = append(ends, w)
+				}
+				rand.Shuffle(len(ends), func(i, j int) { ends[i], ends[j] = ends[j], ends[i] })
+			}
+
+			for _, end := range ends {
+				if _, reachable := parents[end]; !reachable || end == start {
+					continue
+				}
+				ladder := wordLadderPath(parents, end)
+				if !inRange(len(ladder) - 1) {
+					continue
+				}
+				if !topicEnd {
+					log.Printf("Escada de palavras: nenhum par de candidatas a %d-%d passos; usando %q do dicionário", stepRange[0], stepRange[1], end)
+				}
+				data.StartWord = start
+				data.EndWord = end
+				data.Ladder = ladder
+				data.Steps = len(ladder) - 1
+				data.CandidateWords = nil
+				return nil
+			}
+		}
+	}
+	return invalidPuzzle(fmt.Errorf("nenhuma escada de %d a %d passos encontrada para as palavras candidatas", stepRange[0], stepRange[1]))
+}
+
+// wordLadderSearch faz uma busca em largura a partir de start e retorna, para cada palavra alcançável,
+// a palavra anterior no caminho mais curto (start aponta para "").
+func wordLadderSearch(start string, words map[string]bool) map[string]string {
+	parents := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 {
+		word := queue[0]
+		queue = queue[1:]
+		for _, next := range wordLadderNeighbors(word, words) {
+			if _, visited := parents[next]; !visited {
+				parents[next] = word
+				queue = append(queue, next)
+			}
+		}
+	}
+	return parents
+}
+
+// wordLadderNeighbors retorna as palavras do dicionário que diferem de word em exatamente uma letra.
+func wordLadderNeighbors(word string, words map[string]bool) []string {
+	var neighbors []string
+	letters := []byte(word)
+	for i, original := range letters {
+		for c := byte('A'); c <= 'Z'; c++ {
+			if c == original {
+				continue
+			}
+			letters[i] = c
+			if words[string(letters)] {
+				neighbors = append(neighbors, string(letters))
+			}
+		}
+		letters[i] = original
+	}
+	return neighbors
+}
+
+// wordLadderPath reconstrói o caminho mais curto até end, da palavra inicial até end (inclusive).
+func wordLadderPath(parents map[string]string, end string) []string {
+	var path []string
+	for w := end; w != ""; w = parents[w] {
+		path = append(path, w)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
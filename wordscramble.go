@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+)
+
+// O Gemini escolhe as palavras e as dicas; o proxy embaralha as letras.
+func init() {
+	RegisterGameType(GameTypeSpec[WordScrambleData]{
+		Name:        "wordscramble",
+		Description: "word scramble puzzle",
+		DataField:   "wordScrambleData",
+		Prompt:      "wordscramble",
+		PostProcess: func(data *WordScrambleData, _ PuzzleRequest) error {
+			if err := scrambleWords(data); err != nil {
+				return invalidPuzzle(err) // Poucas palavras aproveitáveis; o Gemini pode sugerir outras.
+			}
+			return nil
+		},
+	})
+}
+
+// scrambleMaxShuffles limita as tentativas de embaralhar uma palavra. Palavras com ao menos duas letras
+// distintas quase sempre são resolvidas nas primeiras tentativas; o limite cobre casos como anagramas
+// de duas letras ("NO"/"ON") que não têm embaralhamento válido.
+const scrambleMaxShuffles = 100
+
+// minScrambleWords é a quantidade mínima de palavras que um embaralhador deve manter.
+const minScrambleWords = 3
+
+// scrambleWords preenche Scrambled em cada palavra. O embaralhamento nunca é igual à própria palavra
+// nem a outra palavra da lista. Palavras repetidas ou impossíveis de embaralhar são descartadas.
+func scrambleWords(data *WordScrambleData) error {
+	// Palavras da lista em maiúsculas, para comparar embaralhamentos sem diferenciar maiúsculas.
+	answers := make(map[string]bool, len(data.Words))
+	for _, w := range data.Words {
+		answers[strings.ToUpper(strings.TrimSpace(w.Word))] = true
+	}
+
+	kept := data.Words[:0]
+	seen := make(map[string]bool, len(data.Words))
+	for _, w := range data.Words {
+		w.Word = strings.ToUpper(strings.TrimSpace(w.Word))
+		if w.Word == "" || seen[w.Word] {
+			log.Printf("Embaralhador: palavra vazia ou repetida descartada: %q", w.Word)
+			continue
+		}
+		seen[w.Word] = true
+
+		scrambled, ok := scrambleWord(w.Word, answers)
+		if !ok {
+			log.Printf("Embaralhador: palavra sem embaralhamento válido descartada: %q", w.Word)
+			continue
+		}
+		w.Scrambled = scrambled
+		kept = append(kept, w)
+	}
+
+	if len(kept) < minScrambleWords {
+		return fmt.Errorf("apenas %d palavras válidas, mínimo de %d", len(kept), minScrambleWords)
+	}
+	data.Words = kept
+	return nil
+}
+
+// scrambleWord embaralha as letras da palavra (por runa, preservando acentos) até obter uma sequência
+// que não esteja em answers. Retorna false se nenhuma for encontrada.
+func scrambleWord(word string, answers map[string]bool) (string, bool) {
+	letters := []rune(word)
+	if strings.Count(word, string(letters[0])) == len(letters) {
+		return "", false // Uma letra só ou todas iguais: qualquer embaralhamento é a própria palavra.
+	}
+	for i := 0; i < scrambleMaxShuffles; i++ {
+		rand.Shuffle(len(letters), func(a, b int) { letters[a], letters[b] = letters[b], letters[a] })
+		if candidate := string(letters); !answers[candidate] {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScrambleWords(t *testing.T) {
+	// Repete porque o embaralhamento é aleatório.
+	for i := 0; i < 200; i++ {
+		data := &WordScrambleData{Words: []ScrambleWord{
+			{Word: "gato"}, {Word: "Cachorro"}, {Word: "NO"}, {Word: "ON"}, {Word: "A"},
+			{Word: "AAA"}, {Word: "ação"}, {Word: "GATO "}, {Word: ""},
+		}}
+		if err := scrambleWords(data); err != nil {
+			t.Fatal(err)
+		}
+
+		var words []string
+		for _, w := range data.Words {
+			words = append(words, w.Word)
+			if w.Scrambled == w.Word {
+				t.Fatalf("%q embaralhada como ela mesma", w.Word)
+			}
+			// NO e ON só podem ser embaralhadas uma como a outra, então são descartadas.
+			if slices.ContainsFunc(data.Words, func(other ScrambleWord) bool { return other.Word == w.Scrambled }) {
+				t.Fatalf("%q embaralhada como outra palavra da lista: %q", w.Word, w.Scrambled)
+			}
+			got, want := []rune(w.Scrambled), []rune(w.Word)
+			slices.Sort(got)
+			slices.Sort(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%q embaralhada como %q, que não tem as mesmas letras", w.Word, w.Scrambled)
+			}
+		}
+		// Palavras vazias, repetidas ou sem embaralhamento válido são descartadas.
+		if want := []string{"GATO", "CACHORRO", "AÇÃO"}; !reflect.DeepEqual(words, want) {
+			t.Fatalf("palavras mantidas = %v, quer %v", words, want)
+		}
+	}
+}
+
+// Palavras sem embaralhamento possível são recusadas de imediato, e uma lista sem palavras suficientes
+// resulta em erro em vez de um embaralhador com menos de minScrambleWords palavras.
+func TestScrambleWordsRejectsUnscramblable(t *testing.T) {
+	for _, word := range []string{"A", "Ç", "AAAA", "ÃÃ"} {
+		if scrambled, ok := scrambleWord(word, map[string]bool{word: true}); ok {
+			t.Errorf("scrambleWord(%q) = %q, quer false", word, scrambled)
+		}
+	}
+	if scrambled, ok := scrambleWord("NO", map[string]bool{"NO": true, "ON": true}); ok {
+		t.Errorf("scrambleWord(NO) com ON na lista = %q, quer false", scrambled)
+	}
+
+	data := &WordScrambleData{Words: []ScrambleWord{{Word: "A"}, {Word: "BB"}, {Word: "NO"}, {Word: "ON"}, {Word: "GATO"}}}
+	err := scrambleWords(data)
+	if err == nil || !strings.Contains(err.Error(), "apenas 1 palavras válidas") {
+		t.Errorf("scrambleWords = %v, quer erro com apenas 1 palavra válida", err)
+	}
+}
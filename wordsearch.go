@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// O Gemini escolhe apenas as palavras e o tamanho da grade; o aplicativo monta a grade de letras.
+// O proxy verifica se cada palavra cabe na grade, para que o aplicativo sempre consiga posicioná-la.
+func init() {
+	RegisterGameType(GameTypeSpec[WordSearchData]{
+		Name:        "wordsearch",
+		Description: "word search puzzle",
+		DataField:   "wordSearchData",
+		Prompt:      "wordsearch",
+		Validate: func(data *WordSearchData, _ PuzzleRequest) error {
+			return validateWordSearchData(data)
+		},
+	})
+}
+
+// validateWordSearchData verifica o caça-palavras: grade com dimensões positivas e cada palavra só com letras
+// e no máximo tão longa quanto a maior dimensão da grade (a maior linha ou coluna em que pode ser posicionada).
+// As palavras são normalizadas para maiúsculas.
+func validateWordSearchData(data *WordSearchData) error {
+	rows, cols := data.GridSize.Rows, data.GridSize.Cols
+	if rows <= 0 || cols <= 0 {
+		return fmt.Errorf("gridSize inválido: %dx%d", rows, cols)
+	}
+	if len(data.WordsToFind) == 0 {
+		return fmt.Errorf("nenhuma palavra em wordsToFind")
+	}
+
+	longest := max(rows, cols)
+	for i, word := range data.WordsToFind {
+		word = strings.ToUpper(strings.TrimSpace(word))
+		letters := []rune(word)
+		if len(letters) == 0 {
+			return fmt.Errorf("palavra %d vazia", i+1)
+		}
+		for _, r := range letters {
+			if !unicode.IsLetter(r) {
+				return fmt.Errorf("palavra %q: contém %q, que não é uma letra", word, r)
+			}
+		}
+		if len(letters) > longest {
+			return fmt.Errorf("palavra %q tem %d letras e não cabe na grade %dx%d", word, len(letters), rows, cols)
+		}
+		data.WordsToFind[i] = word
+	}
+	return nil
+}